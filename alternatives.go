@@ -0,0 +1,106 @@
+package osrm
+
+import (
+	"sort"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// RouteCost evaluates a route for ranking alternatives, the lower cost is the better route is
+type RouteCost func(Route) float64
+
+// Route costs by OSRM route properties
+var (
+	RouteCostDuration RouteCost = func(r Route) float64 { return float64(r.Duration) }
+	RouteCostDistance RouteCost = func(r Route) float64 { return float64(r.Distance) }
+	RouteCostWeight   RouteCost = func(r Route) float64 { return float64(r.Wieght) }
+)
+
+// RankRoutes returns routes sorted by the given cost, the best route goes first.
+// Routes of the same cost keep the order returned by OSRM.
+func RankRoutes(routes []Route, cost RouteCost) []Route {
+	ranked := make([]Route, len(routes))
+	copy(ranked, routes)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return cost(ranked[i]) < cost(ranked[j])
+	})
+	return ranked
+}
+
+// FilterRoutesByStretch drops routes which duration exceeds the duration of the fastest route
+// more than maxStretch times, e.g. 1.3 keeps routes at most 30% slower than the fastest one.
+func FilterRoutesByStretch(routes []Route, maxStretch float64) []Route {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	fastest := routes[0].Duration
+	for _, r := range routes[1:] {
+		if r.Duration < fastest {
+			fastest = r.Duration
+		}
+	}
+
+	var filtered []Route
+	for _, r := range routes {
+		if float64(r.Duration) <= float64(fastest)*maxStretch {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// RouteOverlap returns the share of the route a length which is shared with the route b.
+// Routes are compared by their geometry segments, thus both routes should be requested
+// with the full overview and the same geometries format.
+func RouteOverlap(a, b Route) float64 {
+	shared := make(map[[2]geo.Point]bool, b.Geometry.Length())
+	for _, s := range routeSegments(b) {
+		shared[s] = true
+	}
+
+	var total, overlap float64
+	for _, s := range routeSegments(a) {
+		length := s[0].GeoDistanceFrom(&s[1])
+		total += length
+		if shared[s] {
+			overlap += length
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return overlap / total
+}
+
+func routeSegments(r Route) [][2]geo.Point {
+	ps := r.Geometry.PointSet
+	if len(ps) < 2 {
+		return nil
+	}
+	segments := make([][2]geo.Point, 0, len(ps)-1)
+	for i := 1; i < len(ps); i++ {
+		// the geometry is decoded from a polyline, so rounding makes points of the same location equal
+		segments = append(segments, [2]geo.Point{*ps[i-1].Clone().Round(polyline6Factor), *ps[i].Clone().Round(polyline6Factor)})
+	}
+	return segments
+}
+
+// DistinctRoutes drops routes which share more than maxOverlap of their length with a better route.
+// The routes should be ranked, e.g. with RankRoutes, since the first route is always kept.
+func DistinctRoutes(routes []Route, maxOverlap float64) []Route {
+	var distinct []Route
+	for _, r := range routes {
+		keep := true
+		for _, d := range distinct {
+			if RouteOverlap(r, d) > maxOverlap {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			distinct = append(distinct, r)
+		}
+	}
+	return distinct
+}
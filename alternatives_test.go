@@ -0,0 +1,69 @@
+package osrm
+
+import (
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+)
+
+var alternativeRoutes = []Route{
+	{
+		Distance: 1000,
+		Duration: 120,
+		Wieght:   130,
+		Geometry: NewGeometryFromPointSet(geo.PointSet{{0, 0}, {0, 0.001}, {0, 0.002}, {0, 0.003}}),
+	},
+	{
+		Distance: 900,
+		Duration: 100,
+		Wieght:   140,
+		Geometry: NewGeometryFromPointSet(geo.PointSet{{0, 0}, {0, 0.001}, {0.001, 0.002}, {0, 0.003}}),
+	},
+	{
+		Distance: 1500,
+		Duration: 200,
+		Wieght:   200,
+		Geometry: NewGeometryFromPointSet(geo.PointSet{{0, 0}, {-0.001, 0.001}, {-0.001, 0.002}, {0, 0.003}}),
+	},
+}
+
+func TestRankRoutes(t *testing.T) {
+	durations := func(routes []Route) []float32 {
+		var d []float32
+		for _, r := range routes {
+			d = append(d, r.Duration)
+		}
+		return d
+	}
+
+	assert.Equal(t, []float32{100, 120, 200}, durations(RankRoutes(alternativeRoutes, RouteCostDuration)))
+	assert.Equal(t, []float32{100, 120, 200}, durations(RankRoutes(alternativeRoutes, RouteCostDistance)))
+	assert.Equal(t, []float32{120, 100, 200}, durations(RankRoutes(alternativeRoutes, RouteCostWeight)))
+	assert.Equal(t, []float32{200, 120, 100}, durations(RankRoutes(alternativeRoutes, func(r Route) float64 {
+		return -float64(r.Distance)
+	})))
+	// the input is not modified
+	assert.Equal(t, []float32{120, 100, 200}, durations(alternativeRoutes))
+}
+
+func TestFilterRoutesByStretch(t *testing.T) {
+	assert.Len(t, FilterRoutesByStretch(alternativeRoutes, 1.5), 2)
+	assert.Len(t, FilterRoutesByStretch(alternativeRoutes, 2), 3)
+	assert.Len(t, FilterRoutesByStretch(alternativeRoutes, 1), 1)
+	assert.Nil(t, FilterRoutesByStretch(nil, 1))
+}
+
+func TestRouteOverlap(t *testing.T) {
+	assert.InDelta(t, 1, RouteOverlap(alternativeRoutes[0], alternativeRoutes[0]), 1e-9)
+	assert.InDelta(t, 1.0/3, RouteOverlap(alternativeRoutes[0], alternativeRoutes[1]), 1e-6)
+	assert.Equal(t, 0.0, RouteOverlap(alternativeRoutes[0], alternativeRoutes[2]))
+	assert.Equal(t, 0.0, RouteOverlap(Route{}, alternativeRoutes[0]))
+}
+
+func TestDistinctRoutes(t *testing.T) {
+	routes := append([]Route{alternativeRoutes[0]}, alternativeRoutes...)
+
+	assert.Equal(t, alternativeRoutes, DistinctRoutes(routes, 0.5))
+	assert.Equal(t, []Route{alternativeRoutes[0], alternativeRoutes[2]}, DistinctRoutes(routes, 0.2))
+}
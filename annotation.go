@@ -0,0 +1,163 @@
+package osrm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Annotation contains additional metadata for each coordinate along the route geometry.
+// Values of Duration, Distance, Speed, Weight and Datasources describe segments between
+// adjacent coordinates, while Nodes contains OSM node ids of the coordinates.
+type Annotation struct {
+	Duration    []float32           `json:"duration,omitempty"`
+	Distance    []float32           `json:"distance,omitempty"`
+	Nodes       []uint64            `json:"nodes,omitempty"`
+	Speed       []float32           `json:"speed,omitempty"`
+	Weight      []float32           `json:"weight,omitempty"`
+	Datasources []uint32            `json:"datasources,omitempty"`
+	Metadata    *AnnotationMetadata `json:"metadata,omitempty"`
+}
+
+// AnnotationMetadata contains metadata related to other annotations
+type AnnotationMetadata struct {
+	// DatasourceNames are names of the datasources referenced by Annotation.Datasources.
+	// The name "lua profile" stands for the speeds of the profile, others are names of loaded traffic files.
+	DatasourceNames []string `json:"datasource_names"`
+}
+
+// UnmarshalJSON decodes annotations and validates that they could be aligned by segment index
+func (a *Annotation) UnmarshalJSON(b []byte) error {
+	type annotation Annotation // prevents recursive UnmarshalJSON calls
+	if err := json.Unmarshal(b, (*annotation)(a)); err != nil {
+		return err
+	}
+	return a.Validate()
+}
+
+// Validate checks that all segment annotations have the same number of values.
+// Nodes are not validated since OSRM reports them per coordinate.
+func (a Annotation) Validate() error {
+	n := a.Segments()
+	for _, v := range []struct {
+		name string
+		len  int
+	}{
+		{"duration", len(a.Duration)},
+		{"distance", len(a.Distance)},
+		{"speed", len(a.Speed)},
+		{"weight", len(a.Weight)},
+		{"datasources", len(a.Datasources)},
+	} {
+		if v.len != 0 && v.len != n {
+			return fmt.Errorf("%w: %s has %d values, expected %d", ErrAnnotationLengthMismatch, v.name, v.len, n)
+		}
+	}
+	return nil
+}
+
+// AnnotationSegment represents annotations of a segment between two adjacent coordinates
+type AnnotationSegment struct {
+	Index      int
+	Duration   float32
+	Distance   float32
+	Speed      float32
+	Weight     float32
+	Datasource string
+}
+
+// Segments returns the number of annotated segments
+func (a Annotation) Segments() int {
+	n := 0
+	for _, l := range []int{len(a.Duration), len(a.Distance), len(a.Speed), len(a.Weight), len(a.Datasources)} {
+		if l > n {
+			n = l
+		}
+	}
+	return n
+}
+
+// Segment returns annotations of i-th segment.
+// Missing annotations are left zero, speed is derived from distance and duration if not requested.
+func (a Annotation) Segment(i int) AnnotationSegment {
+	s := AnnotationSegment{Index: i}
+	if i < len(a.Duration) {
+		s.Duration = a.Duration[i]
+	}
+	if i < len(a.Distance) {
+		s.Distance = a.Distance[i]
+	}
+	if i < len(a.Weight) {
+		s.Weight = a.Weight[i]
+	}
+	if i < len(a.Speed) {
+		s.Speed = a.Speed[i]
+	} else if s.Duration > 0 {
+		s.Speed = s.Distance / s.Duration
+	}
+	if i < len(a.Datasources) {
+		s.Datasource = a.datasourceName(a.Datasources[i])
+	}
+	return s
+}
+
+func (a Annotation) datasourceName(i uint32) string {
+	if a.Metadata != nil && int(i) < len(a.Metadata.DatasourceNames) {
+		return a.Metadata.DatasourceNames[i]
+	}
+	return strconv.FormatUint(uint64(i), 10)
+}
+
+// AverageSpeed returns time-weighted average speed in meters per second.
+// It requires distance and duration annotations, otherwise false is returned.
+func (a Annotation) AverageSpeed() (float64, bool) {
+	var distance, duration float64
+	for i := 0; i < len(a.Duration) && i < len(a.Distance); i++ {
+		distance += float64(a.Distance[i])
+		duration += float64(a.Duration[i])
+	}
+	if duration == 0 {
+		return 0, false
+	}
+	return distance / duration, true
+}
+
+// SlowestSegment returns the segment with the lowest speed.
+// Segments of zero duration (e.g. waypoints snapped to the same location) are skipped.
+// It requires either speed or distance and duration annotations, otherwise false is returned.
+func (a Annotation) SlowestSegment() (AnnotationSegment, bool) {
+	var (
+		slowest AnnotationSegment
+		found   bool
+	)
+	for i := 0; i < a.Segments(); i++ {
+		s := a.Segment(i)
+		if s.Duration == 0 && i >= len(a.Speed) {
+			continue
+		}
+		if !found || s.Speed < slowest.Speed {
+			slowest, found = s, true
+		}
+	}
+	return slowest, found
+}
+
+// DatasourceShares returns the share of duration spent on segments of each datasource,
+// e.g. how much of the time is estimated from live traffic and how much from the profile speeds.
+// Datasources are keyed by their names if metadata is available and by their indexes otherwise.
+// It requires datasources and duration annotations, otherwise nil is returned.
+func (a Annotation) DatasourceShares() map[string]float64 {
+	var total float64
+	durations := map[string]float64{}
+	for i := 0; i < len(a.Datasources) && i < len(a.Duration); i++ {
+		durations[a.datasourceName(a.Datasources[i])] += float64(a.Duration[i])
+		total += float64(a.Duration[i])
+	}
+	if total == 0 {
+		return nil
+	}
+	for k, v := range durations {
+		durations[k] = v / total
+	}
+	return durations
+}
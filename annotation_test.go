@@ -0,0 +1,82 @@
+package osrm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalAnnotation(t *testing.T) {
+	var a Annotation
+	err := json.Unmarshal([]byte(`{
+		"duration": [2, 10, 4],
+		"distance": [20, 50, 80],
+		"speed": [10, 5, 20],
+		"weight": [2.5, 10, 4],
+		"datasources": [0, 1, 1],
+		"nodes": [1, 2, 3, 4],
+		"metadata": {"datasource_names": ["lua profile", "traffic"]}
+	}`), &a)
+
+	require.NoError(t, err)
+	assert.Equal(t, []float32{10, 5, 20}, a.Speed)
+	assert.Equal(t, []float32{2.5, 10, 4}, a.Weight)
+	assert.Equal(t, []uint32{0, 1, 1}, a.Datasources)
+	assert.Equal(t, &AnnotationMetadata{DatasourceNames: []string{"lua profile", "traffic"}}, a.Metadata)
+	assert.Equal(t, 3, a.Segments())
+	assert.Equal(t, AnnotationSegment{
+		Index:      1,
+		Duration:   10,
+		Distance:   50,
+		Speed:      5,
+		Weight:     10,
+		Datasource: "traffic",
+	}, a.Segment(1))
+
+	speed, ok := a.AverageSpeed()
+	require.True(t, ok)
+	assert.InDelta(t, 150.0/16, speed, 1e-9)
+
+	slowest, ok := a.SlowestSegment()
+	require.True(t, ok)
+	assert.Equal(t, 1, slowest.Index)
+
+	assert.Equal(t, map[string]float64{"lua profile": 0.125, "traffic": 0.875}, a.DatasourceShares())
+}
+
+func TestAnnotationAggregatesWithoutSpeed(t *testing.T) {
+	a := Annotation{
+		Duration:    []float32{0, 2, 4},
+		Distance:    []float32{0, 30, 20},
+		Datasources: []uint32{0, 0, 2},
+	}
+
+	slowest, ok := a.SlowestSegment()
+	require.True(t, ok)
+	assert.Equal(t, 2, slowest.Index)
+	assert.Equal(t, float32(5), slowest.Speed)
+
+	assert.Equal(t, map[string]float64{"0": 2.0 / 6, "2": 4.0 / 6}, a.DatasourceShares())
+}
+
+func TestAnnotationAggregatesWithoutData(t *testing.T) {
+	var a Annotation
+
+	_, ok := a.AverageSpeed()
+	assert.False(t, ok)
+	_, ok = a.SlowestSegment()
+	assert.False(t, ok)
+	assert.Nil(t, a.DatasourceShares())
+}
+
+func TestUnmarshalAnnotationWithLengthMismatch(t *testing.T) {
+	var a Annotation
+	err := json.Unmarshal([]byte(`{"duration": [1, 2, 3], "speed": [1, 2], "nodes": [1, 2, 3, 4]}`), &a)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrAnnotationLengthMismatch))
+	assert.EqualError(t, err, "osrm5: annotations have different number of values: speed has 2 values, expected 3")
+}
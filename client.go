@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 type (
@@ -17,32 +18,54 @@ type (
 		Do(*http.Request) (*http.Response, error)
 	}
 
+	// RequestMutator modifies a request before it is sent to OSRM server.
+	// It could be used to add auth tokens or tracing headers.
+	RequestMutator func(*http.Request) error
+
 	// client makes a real query to OSRM server
 	client struct {
 		httpClient HTTPClient
 		serverURL  string
+		headers    http.Header
+		mutator    RequestMutator
+		versions   *dataVersionTracker
 	}
 )
 
 // newClient creates a client with server url and specific getter
 func newClient(serverURL string, c HTTPClient) client {
-	return client{c, serverURL}
+	return client{httpClient: c, serverURL: serverURL}
 }
 
-// doRequest makes GET request to OSRM server and decodes the given JSON
+// doRequest makes GET request to OSRM server and decodes the response in the requested format
 func (c client) doRequest(ctx context.Context, in *request, out interface{}) error {
 	url, err := in.URL(c.serverURL)
 	if err != nil {
 		return err
 	}
 
+	raw := rawResponseFromContext(ctx)
+	if raw != nil {
+		*raw = RawResponse{URL: url}
+	}
+
+	start := time.Now()
 	resp, err := c.get(ctx, url)
 	if err != nil {
+		if raw != nil {
+			raw.Latency = time.Since(start)
+		}
 		return err
 	}
 	defer closeSilently(resp.Body)
 
 	bytes, err := ioutil.ReadAll(resp.Body)
+	if raw != nil {
+		raw.StatusCode = resp.StatusCode
+		raw.Header = resp.Header
+		raw.Body = bytes
+		raw.Latency = time.Since(start)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to read body: %v", err)
 	}
@@ -54,10 +77,17 @@ func (c client) doRequest(ctx context.Context, in *request, out interface{}) err
 		return fmt.Errorf("unexpected http status code %d with body %q", resp.StatusCode, bytes)
 	}
 
-	if err := json.Unmarshal(bytes, out); err != nil {
+	if in.format == FormatFlatbuffers {
+		if err := unmarshalFlatbuffers(bytes, out); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(bytes, out); err != nil {
 		return fmt.Errorf("failed to unmarshal body %q: %v", bytes, err)
 	}
 
+	if r, ok := out.(response); ok {
+		c.versions.observe(backendURL(resp, c.serverURL), r.dataVersion())
+	}
 	return nil
 }
 
@@ -67,6 +97,17 @@ func (c client) get(ctx context.Context, url string) (*http.Response, error) {
 		return nil, err
 	}
 
+	for k, values := range c.headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	if c.mutator != nil {
+		if err := c.mutator(req); err != nil {
+			return nil, fmt.Errorf("failed to modify request: %v", err)
+		}
+	}
+
 	return c.httpClient.Do(req.WithContext(ctx))
 }
 
@@ -2,6 +2,7 @@ package osrm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -49,3 +50,81 @@ func Test_doRequestWithBodyUnmarshalFailure(t *testing.T) {
 	err := c.doRequest(context.Background(), &req, nil)
 	require.EqualError(t, err, "failed to unmarshal body \"\": unexpected end of JSON input")
 }
+
+func Test_getWithHeadersAndMutator(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer ts.Close()
+
+	c := newClient(ts.URL, &http.Client{})
+	c.headers = http.Header{
+		"User-Agent": {"go-osrm-test"},
+		"x-tenant":   {"acme"},
+	}
+	c.mutator = func(r *http.Request) error {
+		r.Header.Set("Authorization", "Bearer token")
+		return nil
+	}
+	resp, err := c.get(context.Background(), ts.URL)
+	require.NoError(t, err)
+	closeSilently(resp.Body)
+
+	require.Equal(t, "go-osrm-test", header.Get("User-Agent"))
+	require.Equal(t, []string{"acme"}, header["X-Tenant"])
+	require.Equal(t, "Bearer token", header.Get("Authorization"))
+}
+
+func Test_getWithMutatorFailure(t *testing.T) {
+	c := newClient("/", &http.Client{})
+	c.mutator = func(r *http.Request) error {
+		return errors.New("no token")
+	}
+	resp, err := c.get(context.Background(), "/")
+	require.Nil(t, resp)
+	require.EqualError(t, err, "failed to modify request: no token")
+}
+
+func Test_doRequestWithRawResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Osrm", "yes")
+		w.WriteHeader(500)
+		fmt.Fprint(w, "<html><head>")
+	}))
+	defer ts.Close()
+
+	c := newClient(ts.URL, &http.Client{})
+	req := request{
+		profile: "something",
+		coords:  geometry,
+		service: "foobar",
+	}
+	var raw RawResponse
+	err := c.doRequest(WithRawResponse(context.Background(), &raw), &req, nil)
+	require.Error(t, err)
+	require.Equal(t, ts.URL+"/foobar/v1/something/polyline(%7BaowFrerbM%7DPbI~Jyd@)", raw.URL)
+	require.Equal(t, 500, raw.StatusCode)
+	require.Equal(t, "yes", raw.Header.Get("X-Osrm"))
+	require.Equal(t, "<html><head>", string(raw.Body))
+	require.True(t, raw.Latency > 0)
+}
+
+func Test_doRequestWithRawResponseOnTransportFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	c := newClient(url, &http.Client{})
+	req := request{
+		profile: "something",
+		coords:  geometry,
+		service: "foobar",
+	}
+	var raw RawResponse
+	err := c.doRequest(WithRawResponse(context.Background(), &raw), &req, nil)
+	require.Error(t, err)
+	require.Equal(t, url+"/foobar/v1/something/polyline(%7BaowFrerbM%7DPbI~Jyd@)", raw.URL)
+	require.Equal(t, 0, raw.StatusCode)
+	require.Nil(t, raw.Body)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	osrm "github.com/openmarketplaceengine/go-osrm"
+	geo "github.com/paulmach/go.geo"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// Input formats of coordinates
+const (
+	inputCSV     = "csv"
+	inputGeoJSON = "geojson"
+	inputGPX     = "gpx"
+)
+
+var errNoCoordinates = errors.New("no coordinates, use -coords or -input")
+
+// input represents coordinates of a request with optional timestamps and radiuses of GPX and CSV traces
+type input struct {
+	points     geo.PointSet
+	timestamps []int64
+	radiuses   []float64
+}
+
+// parseCoords parses coordinates in OSRM URL form, e.g. "13.38886,52.517037;13.397634,52.529407"
+func parseCoords(s string) (geo.PointSet, error) {
+	var ps geo.PointSet
+	for _, pair := range strings.Split(s, ";") {
+		lnglat := strings.Split(strings.TrimSpace(pair), ",")
+		if len(lnglat) != 2 {
+			return nil, fmt.Errorf("invalid coordinate %q, expected longitude,latitude", pair)
+		}
+		p, err := parsePoint(lnglat[0], lnglat[1])
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func parsePoint(lng, lat string) (geo.Point, error) {
+	x, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("invalid longitude %q", lng)
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return geo.Point{}, fmt.Errorf("invalid latitude %q", lat)
+	}
+	return geo.Point{x, y}, nil
+}
+
+// inputFormat detects the format by the file extension unless it's set explicitly
+func inputFormat(format, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = inputCSV
+		case ".geojson", ".json":
+			format = inputGeoJSON
+		case ".gpx":
+			format = inputGPX
+		default:
+			return "", fmt.Errorf("unknown format of %q, use -input-format", path)
+		}
+	}
+	switch format {
+	case inputCSV, inputGeoJSON, inputGPX:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported input format %q", format)
+	}
+}
+
+func readInput(r io.Reader, format string) (input, error) {
+	switch format {
+	case inputCSV:
+		return readCSV(r)
+	case inputGeoJSON:
+		return readGeoJSON(r)
+	default:
+		req, err := osrm.ParseGPX(r)
+		if err != nil {
+			return input{}, err
+		}
+		return input{points: req.Coordinates.PointSet, timestamps: req.Timestamps, radiuses: req.Radiuses}, nil
+	}
+}
+
+// readCSV reads longitude, latitude and optional timestamp columns.
+// Columns are selected by the header if the first row isn't numeric, e.g. "lat,lon,time".
+func readCSV(r io.Reader) (input, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return input{}, err
+	}
+	if len(rows) == 0 {
+		return input{}, errNoCoordinates
+	}
+
+	lng, lat, timestamp := 0, 1, 2
+	if _, err := strconv.ParseFloat(strings.TrimSpace(rows[0][0]), 64); err != nil {
+		lng, lat, timestamp = -1, -1, -1
+		for i, name := range rows[0] {
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "lng", "lon", "longitude", "x":
+				lng = i
+			case "lat", "latitude", "y":
+				lat = i
+			case "timestamp", "time":
+				timestamp = i
+			}
+		}
+		if lng < 0 || lat < 0 {
+			return input{}, fmt.Errorf("CSV header %q has no longitude and latitude columns", strings.Join(rows[0], ","))
+		}
+		rows = rows[1:]
+	}
+
+	var in input
+	for n, row := range rows {
+		if lng >= len(row) || lat >= len(row) {
+			return input{}, fmt.Errorf("CSV row %d has %d columns", n+1, len(row))
+		}
+		p, err := parsePoint(row[lng], row[lat])
+		if err != nil {
+			return input{}, err
+		}
+		in.points = append(in.points, p)
+		if timestamp >= 0 && timestamp < len(row) {
+			t, err := strconv.ParseInt(strings.TrimSpace(row[timestamp]), 10, 64)
+			if err != nil {
+				return input{}, fmt.Errorf("invalid timestamp %q", row[timestamp])
+			}
+			in.timestamps = append(in.timestamps, t)
+		}
+	}
+	if len(in.timestamps) != len(in.points) {
+		in.timestamps = nil
+	}
+	return in, nil
+}
+
+// readGeoJSON reads points, multipoints and linestrings of a feature collection, a feature or a geometry
+func readGeoJSON(r io.Reader) (input, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return input{}, err
+	}
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return input{}, err
+	}
+
+	var geometries []*geojson.Geometry
+	switch object.Type {
+	case "FeatureCollection":
+		fc, err := geojson.UnmarshalFeatureCollection(data)
+		if err != nil {
+			return input{}, err
+		}
+		for _, f := range fc.Features {
+			geometries = append(geometries, f.Geometry)
+		}
+	case "Feature":
+		f, err := geojson.UnmarshalFeature(data)
+		if err != nil {
+			return input{}, err
+		}
+		geometries = append(geometries, f.Geometry)
+	default:
+		g, err := geojson.UnmarshalGeometry(data)
+		if err != nil {
+			return input{}, err
+		}
+		geometries = append(geometries, g)
+	}
+
+	var in input
+	add := func(coords ...[]float64) {
+		for _, c := range coords {
+			if len(c) >= 2 {
+				in.points = append(in.points, geo.Point{c[0], c[1]})
+			}
+		}
+	}
+	for _, g := range geometries {
+		switch {
+		case g == nil:
+		case g.IsPoint():
+			add(g.Point)
+		case g.IsMultiPoint():
+			add(g.MultiPoint...)
+		case g.IsLineString():
+			add(g.LineString...)
+		}
+	}
+	if len(in.points) == 0 {
+		return input{}, errNoCoordinates
+	}
+	return in, nil
+}
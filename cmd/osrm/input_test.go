@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoords(t *testing.T) {
+	ps, err := parseCoords("13.38886,52.517037; 13.397634,52.529407")
+	require.NoError(t, err)
+	assert.Equal(t, geo.PointSet{{13.38886, 52.517037}, {13.397634, 52.529407}}, ps)
+
+	_, err = parseCoords("13.38886")
+	assert.EqualError(t, err, `invalid coordinate "13.38886", expected longitude,latitude`)
+	_, err = parseCoords("13.38886,north")
+	assert.EqualError(t, err, `invalid latitude "north"`)
+}
+
+func TestInputFormat(t *testing.T) {
+	for path, expected := range map[string]string{
+		"trace.csv":     inputCSV,
+		"route.geojson": inputGeoJSON,
+		"route.JSON":    inputGeoJSON,
+		"trace.gpx":     inputGPX,
+	} {
+		format, err := inputFormat("", path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, format, path)
+	}
+
+	format, err := inputFormat(inputCSV, "-")
+	require.NoError(t, err)
+	assert.Equal(t, inputCSV, format)
+
+	_, err = inputFormat("", "-")
+	assert.EqualError(t, err, `unknown format of "-", use -input-format`)
+	_, err = inputFormat("kml", "trace.kml")
+	assert.EqualError(t, err, `unsupported input format "kml"`)
+}
+
+func TestReadCSV(t *testing.T) {
+	in, err := readInput(strings.NewReader("13.38886,52.517037\n13.397634,52.529407\n"), inputCSV)
+	require.NoError(t, err)
+	assert.Equal(t, geo.PointSet{{13.38886, 52.517037}, {13.397634, 52.529407}}, in.points)
+	assert.Nil(t, in.timestamps)
+
+	in, err = readInput(strings.NewReader("time,lat,lon\n1424684612,52.517037,13.38886\n1424684616,52.529407,13.397634\n"), inputCSV)
+	require.NoError(t, err)
+	assert.Equal(t, geo.PointSet{{13.38886, 52.517037}, {13.397634, 52.529407}}, in.points)
+	assert.Equal(t, []int64{1424684612, 1424684616}, in.timestamps)
+
+	_, err = readInput(strings.NewReader("name,value\na,1\n"), inputCSV)
+	assert.EqualError(t, err, `CSV header "name,value" has no longitude and latitude columns`)
+	_, err = readInput(strings.NewReader(""), inputCSV)
+	assert.Equal(t, errNoCoordinates, err)
+}
+
+func TestReadGeoJSON(t *testing.T) {
+	fc := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[13.38886,52.517037]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[13.39,52.52],[13.397634,52.529407]]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]},"properties":{}}
+	]}`
+	in, err := readInput(strings.NewReader(fc), inputGeoJSON)
+	require.NoError(t, err)
+	assert.Equal(t, geo.PointSet{{13.38886, 52.517037}, {13.39, 52.52}, {13.397634, 52.529407}}, in.points)
+
+	in, err = readInput(strings.NewReader(`{"type":"MultiPoint","coordinates":[[13.38886,52.517037],[13.39,52.52]]}`), inputGeoJSON)
+	require.NoError(t, err)
+	assert.Len(t, in.points, 2)
+
+	in, err = readInput(strings.NewReader(`{"type":"Feature","geometry":{"type":"Point","coordinates":[13.38886,52.517037]},"properties":{}}`), inputGeoJSON)
+	require.NoError(t, err)
+	assert.Len(t, in.points, 1)
+
+	_, err = readInput(strings.NewReader(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`), inputGeoJSON)
+	assert.Equal(t, errNoCoordinates, err)
+}
+
+func TestReadGPX(t *testing.T) {
+	gpx := `<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><trkseg>
+    <trkpt lat="52.517037" lon="13.38886"><time>2015-02-23T09:43:32Z</time></trkpt>
+    <trkpt lat="52.529407" lon="13.397634"><time>2015-02-23T09:43:36Z</time></trkpt>
+  </trkseg></trk>
+</gpx>`
+	in, err := readInput(strings.NewReader(gpx), inputGPX)
+	require.NoError(t, err)
+	assert.Equal(t, geo.PointSet{{13.38886, 52.517037}, {13.397634, 52.529407}}, in.points)
+	assert.Equal(t, []int64{1424684612, 1424684616}, in.timestamps)
+}
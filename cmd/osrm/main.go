@@ -0,0 +1,371 @@
+// Command osrm queries OSRM services from the command line.
+//
+// Usage:
+//
+//	osrm route|table|match|nearest|trip [flags]
+//
+// Coordinates are given either with -coords in OSRM URL form, e.g. -coords "13.38886,52.517037;13.397634,52.529407",
+// or with -input file in CSV, GeoJSON or GPX format, "-" reads the file from stdin. Responses are printed as JSON,
+// human-readable tables or GeoJSON selected with -output. The server is taken from -server or OSRM_SERVER environment variable.
+//
+// OSRM Trip service isn't supported by the library, thus trip command orders the stops locally with durations
+// of the table service and requests the route through them.
+//
+// The command exits with code 2 on invalid flags and arguments and with code 1 if a request or an input file fails,
+// -h prints flags of the command and exits with code 0.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	osrm "github.com/openmarketplaceengine/go-osrm"
+	geo "github.com/paulmach/go.geo"
+)
+
+const usage = `usage: osrm route|table|match|nearest|trip [flags]
+
+Run "osrm <command> -h" to list flags of the command.`
+
+// Supported commands
+const (
+	commandRoute   = "route"
+	commandTable   = "table"
+	commandMatch   = "match"
+	commandNearest = "nearest"
+	commandTrip    = "trip"
+)
+
+// Output formats
+const (
+	outputJSON    = "json"
+	outputTable   = "table"
+	outputGeoJSON = "geojson"
+)
+
+const defaultServer = "http://127.0.0.1:5000"
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	// help requested with -h isn't a failure
+	if err == nil || err == flag.ErrHelp {
+		return
+	}
+	if !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "osrm:", err)
+	}
+	// usage errors exit with 2 like the flag package does, failed requests and inputs with 1
+	var uerr usageError
+	if errors.As(err, &uerr) {
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+// usageError represents an error of command line arguments
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// config represents flags of a command
+type config struct {
+	server      string
+	profile     string
+	coords      string
+	input       string
+	inputFormat string
+	output      string
+	timeout     time.Duration
+
+	// route and match
+	steps        bool
+	annotations  bool
+	overview     string
+	alternatives int
+	// table
+	sources, destinations string
+	// nearest
+	number int
+	// trip
+	roundtrip bool
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return usageError{flag.ErrHelp}
+	}
+	command := args[0]
+
+	server := os.Getenv("OSRM_SERVER")
+	if server == "" {
+		server = defaultServer
+	}
+
+	var cfg config
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.server, "server", server, "OSRM server URL")
+	fs.StringVar(&cfg.profile, "profile", "car", "OSRM profile")
+	fs.StringVar(&cfg.coords, "coords", "", "coordinates as lng,lat;lng,lat")
+	fs.StringVar(&cfg.input, "input", "", "file with coordinates in CSV, GeoJSON or GPX format, - for stdin")
+	fs.StringVar(&cfg.inputFormat, "input-format", "", "input format: csv, geojson or gpx, detected by the file extension if not set")
+	fs.StringVar(&cfg.output, "output", outputJSON, "output format: json, table or geojson")
+	fs.DurationVar(&cfg.timeout, "timeout", 10*time.Second, "request timeout")
+
+	switch command {
+	case commandRoute, commandMatch, commandTrip:
+		fs.BoolVar(&cfg.steps, "steps", false, "return route steps")
+		fs.BoolVar(&cfg.annotations, "annotations", false, "return route annotations")
+		fs.StringVar(&cfg.overview, "overview", string(osrm.OverviewFull), "overview geometry: full, simplified or false")
+		if command == commandRoute {
+			fs.IntVar(&cfg.alternatives, "alternatives", 0, "number of alternative routes")
+		}
+		if command == commandTrip {
+			fs.BoolVar(&cfg.roundtrip, "roundtrip", true, "return to the first coordinate")
+		}
+	case commandTable:
+		fs.StringVar(&cfg.sources, "sources", "", "indices of source coordinates, e.g. 0,1")
+		fs.StringVar(&cfg.destinations, "destinations", "", "indices of destination coordinates, e.g. 2,3")
+	case commandNearest:
+		fs.IntVar(&cfg.number, "number", 1, "number of nearest segments")
+	default:
+		fmt.Fprintln(stderr, usage)
+		return usageError{fmt.Errorf("unknown command %q", command)}
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return usageError{err}
+	}
+	switch cfg.output {
+	case outputJSON, outputTable, outputGeoJSON:
+	default:
+		return usageError{fmt.Errorf("unsupported output format %q", cfg.output)}
+	}
+
+	in, err := cfg.readInput(stdin)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+	client := osrm.NewFromURLWithTimeout(cfg.server, cfg.timeout)
+
+	var result interface{}
+	switch command {
+	case commandRoute:
+		result, err = cfg.route(ctx, client, in)
+	case commandTable:
+		result, err = cfg.table(ctx, client, in)
+	case commandMatch:
+		result, err = cfg.match(ctx, client, in)
+	case commandNearest:
+		result, err = cfg.nearest(ctx, client, in)
+	case commandTrip:
+		result, err = cfg.trip(ctx, client, in)
+	}
+	if err != nil {
+		return err
+	}
+	return write(stdout, cfg.output, result)
+}
+
+func (cfg config) readInput(stdin io.Reader) (input, error) {
+	switch {
+	case cfg.coords != "" && cfg.input != "":
+		return input{}, usageError{errors.New("use either -coords or -input")}
+	case cfg.coords != "":
+		ps, err := parseCoords(cfg.coords)
+		if err != nil {
+			return input{}, usageError{err}
+		}
+		return input{points: ps}, nil
+	case cfg.input == "":
+		return input{}, usageError{errNoCoordinates}
+	}
+
+	format, err := inputFormat(cfg.inputFormat, cfg.input)
+	if err != nil {
+		return input{}, usageError{err}
+	}
+	if cfg.input == "-" {
+		return readInput(stdin, format)
+	}
+	f, err := os.Open(cfg.input)
+	if err != nil {
+		return input{}, err
+	}
+	defer f.Close()
+	return readInput(f, format)
+}
+
+func (cfg config) stepsOptions() (osrm.Steps, osrm.Annotations, osrm.Overview) {
+	steps, annotations := osrm.StepsFalse, osrm.AnnotationsFalse
+	if cfg.steps {
+		steps = osrm.StepsTrue
+	}
+	if cfg.annotations {
+		annotations = osrm.AnnotationsTrue
+	}
+	return steps, annotations, osrm.Overview(cfg.overview)
+}
+
+func (cfg config) route(ctx context.Context, client *osrm.OSRM, in input) (*osrm.RouteResponse, error) {
+	steps, annotations, overview := cfg.stepsOptions()
+	req := osrm.RouteRequest{
+		Profile:     cfg.profile,
+		Coordinates: osrm.NewGeometryFromPointSet(in.points),
+		Steps:       steps,
+		Annotations: annotations,
+		Overview:    overview,
+		Geometries:  osrm.GeometriesPolyline6,
+	}
+	if cfg.alternatives > 0 {
+		req.Alternatives = osrm.AlternativesNumber(cfg.alternatives)
+	}
+	return client.Route(ctx, req)
+}
+
+func (cfg config) table(ctx context.Context, client *osrm.OSRM, in input) (*tableResult, error) {
+	sources, err := parseIndices(cfg.sources)
+	if err != nil {
+		return nil, usageError{err}
+	}
+	destinations, err := parseIndices(cfg.destinations)
+	if err != nil {
+		return nil, usageError{err}
+	}
+	resp, err := client.Table(ctx, osrm.TableRequest{
+		Profile:      cfg.profile,
+		Coordinates:  osrm.NewGeometryFromPointSet(in.points),
+		Sources:      sources,
+		Destinations: destinations,
+		Annotations:  osrm.NewAnnotations(osrm.AnnotationsDuration, osrm.AnnotationsDistance),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tableResult{TableResponse: resp, sources: sources, destinations: destinations, points: in.points}, nil
+}
+
+func (cfg config) match(ctx context.Context, client *osrm.OSRM, in input) (*osrm.MatchResponse, error) {
+	steps, annotations, overview := cfg.stepsOptions()
+	return client.Match(ctx, osrm.MatchRequest{
+		Profile:     cfg.profile,
+		Coordinates: osrm.NewGeometryFromPointSet(in.points),
+		Timestamps:  in.timestamps,
+		Radiuses:    in.radiuses,
+		Steps:       steps,
+		Annotations: annotations,
+		Overview:    overview,
+		Geometries:  osrm.GeometriesPolyline6,
+	})
+}
+
+func (cfg config) nearest(ctx context.Context, client *osrm.OSRM, in input) (*osrm.NearestResponse, error) {
+	if len(in.points) != 1 {
+		return nil, usageError{fmt.Errorf("nearest needs a single coordinate, got %d", len(in.points))}
+	}
+	return client.Nearest(ctx, osrm.NearestRequest{
+		Profile:     cfg.profile,
+		Coordinates: osrm.NewGeometryFromPointSet(in.points),
+		Number:      cfg.number,
+	})
+}
+
+// tripResult represents the order of visiting the coordinates and the route through them
+type tripResult struct {
+	// WaypointOrder are input indices of coordinates in the order of visiting
+	WaypointOrder []int        `json:"waypoint_order"`
+	Routes        []osrm.Route `json:"routes"`
+}
+
+func (cfg config) trip(ctx context.Context, client *osrm.OSRM, in input) (*tripResult, error) {
+	if len(in.points) < 2 {
+		return nil, usageError{fmt.Errorf("trip needs at least 2 coordinates, got %d", len(in.points))}
+	}
+	table, err := client.Table(ctx, osrm.TableRequest{
+		Profile:     cfg.profile,
+		Coordinates: osrm.NewGeometryFromPointSet(in.points),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the trip starts at the first coordinate visiting the others
+	problem := osrm.NewVRPProblem(*table)
+	problem.Vehicles = []osrm.VRPVehicle{{Start: 0, End: 0, Open: !cfg.roundtrip}}
+	for i := 1; i < len(in.points); i++ {
+		problem.Stops = append(problem.Stops, osrm.VRPStop{Location: i})
+	}
+	solution, err := osrm.SolveVRP(problem, osrm.VRPOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(solution.Unassigned) > 0 {
+		unreachable := make([]int, len(solution.Unassigned))
+		for i, s := range solution.Unassigned {
+			unreachable[i] = problem.Stops[s].Location
+		}
+		return nil, fmt.Errorf("coordinates %v can't be reached", unreachable)
+	}
+
+	order := []int{0}
+	ps := geo.PointSet{in.points[0]}
+	for _, r := range solution.Routes {
+		for _, s := range r.Stops {
+			order = append(order, problem.Stops[s].Location)
+			ps = append(ps, in.points[problem.Stops[s].Location])
+		}
+	}
+	if cfg.roundtrip {
+		ps = append(ps, in.points[0])
+	}
+
+	steps, annotations, overview := cfg.stepsOptions()
+	resp, err := client.Route(ctx, osrm.RouteRequest{
+		Profile:     cfg.profile,
+		Coordinates: osrm.NewGeometryFromPointSet(ps),
+		Steps:       steps,
+		Annotations: annotations,
+		Overview:    overview,
+		Geometries:  osrm.GeometriesPolyline6,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tripResult{WaypointOrder: order, Routes: resp.Routes}, nil
+}
+
+func parseIndices(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var indices []int
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q", v)
+		}
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
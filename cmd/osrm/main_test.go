@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	routeResponse = `{"code":"Ok","routes":[{"distance":1886.3,"duration":246.1,"legs":[{"distance":1886.3,"duration":246.1,"steps":[
+		{"distance":1000,"duration":120,"name":"Friedrichstraße","maneuver":{"type":"depart","bearing_after":0,"location":[13.38886,52.517037]}},
+		{"distance":886.3,"duration":126.1,"name":"Invalidenstraße","maneuver":{"type":"turn","modifier":"right","location":[13.38886,52.526]}},
+		{"distance":0,"duration":0,"name":"Invalidenstraße","maneuver":{"type":"arrive","location":[13.397634,52.529407]}}
+	]}]}],"waypoints":[{"location":[13.38886,52.517037]},{"location":[13.397634,52.529407]}]}`
+	tableResponse   = `{"code":"Ok","durations":[[0,246.1],[250.5,0]],"distances":[[0,1886.3],[1900,0]]}`
+	nearestResponse = `{"code":"Ok","waypoints":[{"location":[13.388799,52.517033],"distance":4.1,"name":"Friedrichstraße"}]}`
+)
+
+// osrmServer responds with canned responses by the service and records request paths
+func osrmServer(t *testing.T, paths *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.String())
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/route/"):
+			_, _ = w.Write([]byte(routeResponse))
+		case strings.HasPrefix(r.URL.Path, "/table/"):
+			_, _ = w.Write([]byte(tableResponse))
+		case strings.HasPrefix(r.URL.Path, "/nearest/"):
+			_, _ = w.Write([]byte(nearestResponse))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+}
+
+func runCommand(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRunRoute(t *testing.T) {
+	var paths []string
+	ts := osrmServer(t, &paths)
+	defer ts.Close()
+
+	out, err := runCommand("route", "-server", ts.URL, "-coords", "13.38886,52.517037;13.397634,52.529407", "-steps")
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Contains(t, paths[0], "/route/v1/car/polyline(")
+	assert.Contains(t, paths[0], "steps=true")
+
+	var resp struct {
+		Routes []struct {
+			Distance float64 `json:"distance"`
+		} `json:"routes"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Len(t, resp.Routes, 1)
+	assert.Equal(t, 1886.3, resp.Routes[0].Distance)
+
+	out, err = runCommand("route", "-server", ts.URL, "-coords", "13.38886,52.517037;13.397634,52.529407", "-output", "table")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"ROUTE  DISTANCE  DURATION",
+		"0      1.9 km    4 min",
+		"       1.0 km    2 min  Head north on Friedrichstraße",
+		"       886 m     2 min  Turn right onto Invalidenstraße",
+		"       0 m       0 s    You have arrived at your destination",
+		"",
+	}, "\n"), out)
+}
+
+func TestRunTable(t *testing.T) {
+	var paths []string
+	ts := osrmServer(t, &paths)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "osrm")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "points.csv")
+	require.NoError(t, ioutil.WriteFile(input, []byte("lon,lat\n13.38886,52.517037\n13.397634,52.529407\n"), 0o600))
+
+	out, err := runCommand("table", "-server", ts.URL, "-input", input, "-output", "table")
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Contains(t, paths[0], "annotations=duration%2Cdistance")
+	assert.Equal(t, strings.Join([]string{
+		"FROM  TO 0            TO 1",
+		"0     0 s / 0 m       4 min / 1.9 km",
+		"1     4 min / 1.9 km  0 s / 0 m",
+		"",
+	}, "\n"), out)
+}
+
+func TestRunNearest(t *testing.T) {
+	var paths []string
+	ts := osrmServer(t, &paths)
+	defer ts.Close()
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader(`{"type":"Point","coordinates":[13.38886,52.517037]}`)
+	err := run(context.Background(), []string{"nearest", "-server", ts.URL, "-input", "-", "-input-format", "geojson", "-output", "geojson", "-number", "2"}, stdin, &stdout, &stderr)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Contains(t, paths[0], "number=2")
+	assert.Contains(t, stdout.String(), `"type":"FeatureCollection"`)
+	assert.Contains(t, stdout.String(), `"name":"Friedrichstraße"`)
+
+	_, err = runCommand("nearest", "-server", ts.URL, "-coords", "13.38886,52.517037;13.397634,52.529407")
+	assert.EqualError(t, err, "nearest needs a single coordinate, got 2")
+}
+
+func TestRunTrip(t *testing.T) {
+	var paths []string
+	ts := osrmServer(t, &paths)
+	defer ts.Close()
+
+	out, err := runCommand("trip", "-server", ts.URL, "-coords", "13.38886,52.517037;13.397634,52.529407", "-output", "table")
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	assert.Contains(t, paths[0], "/table/v1/car/")
+	assert.Contains(t, paths[1], "/route/v1/car/")
+	assert.True(t, strings.HasPrefix(out, "ORDER  0 -> 1\n"), out)
+}
+
+func TestRunUnreachable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":"Ok","durations":[[0,null],[null,0]],"distances":[[0,null],[null,0]]}`))
+	}))
+	defer ts.Close()
+
+	coords := "13.38886,52.517037;13.397634,52.529407"
+	out, err := runCommand("table", "-server", ts.URL, "-coords", coords, "-output", "table")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"FROM  TO 0       TO 1",
+		"0     0 s / 0 m  -",
+		"1     -          0 s / 0 m",
+		"",
+	}, "\n"), out)
+
+	out, err = runCommand("table", "-server", ts.URL, "-coords", coords)
+	require.NoError(t, err)
+	assert.Contains(t, out, "null")
+
+	_, err = runCommand("trip", "-server", ts.URL, "-coords", coords)
+	assert.EqualError(t, err, "coordinates [1] can't be reached")
+}
+
+func TestRunErrors(t *testing.T) {
+	isUsageError := func(err error) bool {
+		var uerr usageError
+		return errors.As(err, &uerr)
+	}
+
+	_, err := runCommand()
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+	assert.True(t, isUsageError(err))
+
+	// help is requested, so it isn't a usage error
+	_, err = runCommand("route", "-h")
+	assert.Equal(t, flag.ErrHelp, err)
+
+	_, err = runCommand("tile")
+	assert.EqualError(t, err, `unknown command "tile"`)
+	assert.True(t, isUsageError(err))
+
+	_, err = runCommand("route", "-unknown")
+	assert.True(t, isUsageError(err))
+
+	_, err = runCommand("route")
+	assert.True(t, errors.Is(err, errNoCoordinates))
+	assert.True(t, isUsageError(err))
+
+	_, err = runCommand("route", "-coords", "1,2", "-input", "trace.gpx")
+	assert.EqualError(t, err, "use either -coords or -input")
+	assert.True(t, isUsageError(err))
+
+	_, err = runCommand("trip", "-coords", "1,2")
+	assert.EqualError(t, err, "trip needs at least 2 coordinates, got 1")
+	assert.True(t, isUsageError(err))
+
+	_, err = runCommand("route", "-coords", "1,2", "-output", "kml")
+	assert.EqualError(t, err, `unsupported output format "kml"`)
+	assert.True(t, isUsageError(err))
+
+	// request failures aren't usage errors
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+	_, err = runCommand("route", "-server", ts.URL, "-coords", "1,2;3,4")
+	require.Error(t, err)
+	assert.False(t, isUsageError(err))
+
+	_, err = runCommand("route", "-input", "missing.csv")
+	require.Error(t, err)
+	assert.False(t, isUsageError(err))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	osrm "github.com/openmarketplaceengine/go-osrm"
+	geo "github.com/paulmach/go.geo"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// tableResult keeps the table response along with the requested coordinates to label rows and columns
+type tableResult struct {
+	*osrm.TableResponse
+	sources, destinations []int
+	points                geo.PointSet
+}
+
+func write(w io.Writer, format string, result interface{}) error {
+	switch format {
+	case outputJSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(result)
+	case outputGeoJSON:
+		fc, err := toGeoJSON(result)
+		if err != nil {
+			return err
+		}
+		bytes, err := fc.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(bytes))
+		return err
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		writeTable(tw, result)
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+func toGeoJSON(result interface{}) (*geojson.FeatureCollection, error) {
+	switch r := result.(type) {
+	case *osrm.RouteResponse:
+		return r.ToGeoJSON(), nil
+	case *osrm.MatchResponse:
+		return r.ToGeoJSON(), nil
+	case *osrm.NearestResponse:
+		return r.ToGeoJSON(), nil
+	case *tripResult:
+		return osrm.RouteResponse{Routes: r.Routes}.ToGeoJSON(), nil
+	case *tableResult:
+		fc := geojson.NewFeatureCollection()
+		for i, p := range r.points {
+			f := geojson.NewPointFeature([]float64{p.Lng(), p.Lat()})
+			f.SetProperty("index", i)
+			fc.AddFeature(f)
+		}
+		return fc, nil
+	default:
+		return nil, fmt.Errorf("GeoJSON output isn't supported for %T", result)
+	}
+}
+
+func writeTable(w io.Writer, result interface{}) {
+	switch r := result.(type) {
+	case *osrm.RouteResponse:
+		writeRoutes(w, r.Routes)
+	case *tripResult:
+		fmt.Fprintf(w, "ORDER\t%s\n", joinInts(r.WaypointOrder))
+		writeRoutes(w, r.Routes)
+	case *osrm.MatchResponse:
+		fmt.Fprintln(w, "MATCHING\tCONFIDENCE\tDISTANCE\tDURATION")
+		for i, m := range r.Matchings {
+			fmt.Fprintf(w, "%d\t%.2f\t%s\t%s\n", i, m.Confidence, formatDistance(float64(m.Distance)), formatDuration(float64(m.Duration)))
+		}
+	case *osrm.NearestResponse:
+		fmt.Fprintln(w, "WAYPOINT\tNAME\tDISTANCE\tLOCATION")
+		for i, wp := range r.Waypoints {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%.6f,%.6f\n", i, wp.Name, formatDistance(wp.Distance), wp.Location.Lng(), wp.Location.Lat())
+		}
+	case *tableResult:
+		writeMatrix(w, r)
+	}
+}
+
+func writeRoutes(w io.Writer, routes []osrm.Route) {
+	instructor := osrm.NewInstructor(osrm.EnglishInstructionCatalog())
+	fmt.Fprintln(w, "ROUTE\tDISTANCE\tDURATION")
+	for i, r := range routes {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i, formatDistance(float64(r.Distance)), formatDuration(float64(r.Duration)))
+		for l, leg := range r.Legs {
+			opts := osrm.InstructionOptions{LegIndex: l, LegCount: len(r.Legs)}
+			for s, instruction := range instructor.LegInstructions(leg, opts) {
+				step := leg.Steps[s]
+				fmt.Fprintf(w, "\t%s\t%s\t%s\n", formatDistance(float64(step.Distance)), formatDuration(float64(step.Duration)), instruction)
+			}
+		}
+	}
+}
+
+// writeMatrix prints durations and distances with sources as rows and destinations as columns
+func writeMatrix(w io.Writer, r *tableResult) {
+	label := func(indices []int, i int) int {
+		if len(indices) > 0 {
+			return indices[i]
+		}
+		return i
+	}
+
+	var header []string
+	if len(r.Durations) > 0 {
+		for j := range r.Durations[0] {
+			header = append(header, fmt.Sprintf("TO %d", label(r.destinations, j)))
+		}
+	}
+	fmt.Fprintf(w, "FROM\t%s\n", strings.Join(header, "\t"))
+	for i, row := range r.Durations {
+		cells := make([]string, len(row))
+		for j, d := range row {
+			// unreachable pairs have infinite durations
+			if math.IsInf(float64(d), 1) {
+				cells[j] = "-"
+				continue
+			}
+			cells[j] = formatDuration(float64(d))
+			if i < len(r.Distances) && j < len(r.Distances[i]) {
+				cells[j] += " / " + formatDistance(float64(r.Distances[i][j]))
+			}
+		}
+		fmt.Fprintf(w, "%d\t%s\n", label(r.sources, i), strings.Join(cells, "\t"))
+	}
+}
+
+func formatDistance(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%.0f m", meters)
+	}
+	return fmt.Sprintf("%.1f km", meters/1000)
+}
+
+func formatDuration(seconds float64) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%.0f s", seconds)
+	}
+	if seconds < 3600 {
+		return fmt.Sprintf("%.0f min", seconds/60)
+	}
+	return fmt.Sprintf("%dh %02.0f min", int(seconds/3600), float64(int(seconds)%3600)/60)
+}
+
+func joinInts(v []int) string {
+	s := make([]string, len(v))
+	for i, x := range v {
+		s[i] = fmt.Sprint(x)
+	}
+	return strings.Join(s, " -> ")
+}
@@ -0,0 +1,108 @@
+package osrm
+
+import (
+	"net/http"
+	"sync"
+)
+
+// DataVersionChange describes a change of data version reported by OSRM backend,
+// which usually means that a new OSM extract has been deployed.
+type DataVersionChange struct {
+	// ServerURL is the scheme and host of the backend which has responded with the new version.
+	// It differs from Config.ServerURL if RequestMutator spreads requests over several backends.
+	ServerURL string
+	Previous  string
+	Current   string
+}
+
+// DataVersionCache is a cache of query results attached to the client with Config.Caches.
+// It is invalidated automatically once a backend changes its data version, so results of different versions aren't mixed.
+type DataVersionCache interface {
+	// InvalidateDataVersion drops results cached for the backend of the change
+	InvalidateDataVersion(DataVersionChange)
+}
+
+// dataVersionTracker keeps the last data version seen in responses of every backend
+type dataVersionTracker struct {
+	// notifyMu serializes observations along with their notifications, so changes are delivered in order
+	notifyMu sync.Mutex
+	mu       sync.Mutex
+	versions map[string]string
+	last     string
+	onChange func(DataVersionChange)
+	caches   []DataVersionCache
+}
+
+func newDataVersionTracker(onChange func(DataVersionChange), caches []DataVersionCache) *dataVersionTracker {
+	return &dataVersionTracker{
+		versions: make(map[string]string),
+		onChange: onChange,
+		caches:   caches,
+	}
+}
+
+// observe saves the data version of the backend and notifies about its change.
+// Empty versions (e.g. in error responses) are ignored.
+func (t *dataVersionTracker) observe(backend, version string) {
+	if t == nil || version == "" {
+		return
+	}
+
+	t.notifyMu.Lock()
+	defer t.notifyMu.Unlock()
+
+	t.mu.Lock()
+	previous := t.versions[backend]
+	t.versions[backend] = version
+	t.last = version
+	t.mu.Unlock()
+
+	// the first seen version isn't a change
+	if previous == "" || previous == version {
+		return
+	}
+	change := DataVersionChange{
+		ServerURL: backend,
+		Previous:  previous,
+		Current:   version,
+	}
+	for _, c := range t.caches {
+		c.InvalidateDataVersion(change)
+	}
+	if t.onChange != nil {
+		t.onChange(change)
+	}
+}
+
+// version returns the last data version seen in responses of any backend
+func (t *dataVersionTracker) version() string {
+	if t == nil {
+		return ""
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+func (t *dataVersionTracker) byBackend() map[string]string {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	versions := make(map[string]string, len(t.versions))
+	for backend, v := range t.versions {
+		versions[backend] = v
+	}
+	return versions
+}
+
+// backendURL returns the scheme and host the response has been received from
+func backendURL(resp *http.Response, serverURL string) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return serverURL
+	}
+	return resp.Request.URL.Scheme + "://" + resp.Request.URL.Host
+}
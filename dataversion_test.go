@@ -0,0 +1,109 @@
+package osrm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataVersionChange(t *testing.T) {
+	versions := []string{"v1", "v1", "", "v2"}
+	n := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"code": "Ok", "data_version": %q}`, versions[n])
+		n++
+	}))
+	defer ts.Close()
+
+	var changes []DataVersionChange
+	osrm := NewWithConfig(Config{
+		ServerURL: ts.URL,
+		OnDataVersionChange: func(c DataVersionChange) {
+			changes = append(changes, c)
+		},
+	})
+	assert.Empty(t, osrm.DataVersion())
+
+	for range versions {
+		_, err := osrm.Table(context.Background(), TableRequest{Profile: "car", Coordinates: geometry})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "v2", osrm.DataVersion())
+	assert.Equal(t, []DataVersionChange{{ServerURL: ts.URL, Previous: "v1", Current: "v2"}}, changes)
+}
+
+type invalidations []DataVersionChange
+
+func (i *invalidations) InvalidateDataVersion(c DataVersionChange) {
+	*i = append(*i, c)
+}
+
+func TestDataVersionPerBackend(t *testing.T) {
+	backend := func(version *string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"code": "Ok", "data_version": %q}`, *version)
+		}))
+	}
+	v1, v2 := "a1", "b1"
+	ts1, ts2 := backend(&v1), backend(&v2)
+	defer ts1.Close()
+	defer ts2.Close()
+
+	// the mutator routes every other request to the second backend
+	n := 0
+	second, err := url.Parse(ts2.URL)
+	require.NoError(t, err)
+	var cache invalidations
+	var changes []DataVersionChange
+	osrm := NewWithConfig(Config{
+		ServerURL: ts1.URL,
+		RequestMutator: func(r *http.Request) error {
+			if n%2 == 1 {
+				r.URL.Host = second.Host
+			}
+			n++
+			return nil
+		},
+		Caches: []DataVersionCache{&cache},
+		OnDataVersionChange: func(c DataVersionChange) {
+			changes = append(changes, c)
+		},
+	})
+
+	table := func() {
+		_, err := osrm.Table(context.Background(), TableRequest{Profile: "car", Coordinates: geometry})
+		require.NoError(t, err)
+	}
+	table()
+	table()
+	assert.Empty(t, changes)
+	assert.Equal(t, map[string]string{ts1.URL: "a1", ts2.URL: "b1"}, osrm.DataVersions())
+
+	v2 = "b2"
+	table()
+	table()
+	expected := []DataVersionChange{{ServerURL: ts2.URL, Previous: "b1", Current: "b2"}}
+	assert.Equal(t, expected, changes)
+	assert.Equal(t, expected, []DataVersionChange(cache))
+	assert.Equal(t, "b2", osrm.DataVersion())
+	assert.Equal(t, map[string]string{ts1.URL: "a1", ts2.URL: "b2"}, osrm.DataVersions())
+}
+
+func TestDataVersionTrackerWithoutCallback(t *testing.T) {
+	tracker := newDataVersionTracker(nil, nil)
+	tracker.observe("http://a", "v1")
+	tracker.observe("http://a", "v2")
+	assert.Equal(t, "v2", tracker.version())
+
+	var nothing *dataVersionTracker
+	nothing.observe("http://a", "v1")
+	assert.Empty(t, nothing.version())
+	assert.Nil(t, nothing.byBackend())
+}
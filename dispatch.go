@@ -0,0 +1,292 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// DispatchStrategy represents the algorithm assigning drivers to riders
+type DispatchStrategy string
+
+// Supported dispatch strategies
+const (
+	// DispatchStrategyOptimal assigns drivers with the Hungarian algorithm maximizing the number of assignments
+	// with the minimal total ETA, it takes cubic time of the number of drivers and riders
+	DispatchStrategyOptimal DispatchStrategy = "optimal"
+	// DispatchStrategyGreedy assigns the closest driver-rider pairs first
+	DispatchStrategyGreedy DispatchStrategy = "greedy"
+)
+
+// String returns DispatchStrategy as a string
+func (s DispatchStrategy) String() string {
+	return string(s)
+}
+
+const defaultDispatchOptimalLimit = 300
+
+// DispatchConfig represents options of driver-rider assignment
+type DispatchConfig struct {
+	// Profile is OSRM profile used for table requests.
+	Profile string
+	// MaxTableSize is the maximal number of coordinates in a table request.
+	// OSRM default of 100 coordinates will be used if not set.
+	MaxTableSize int
+	// Strategy selects the assignment algorithm. If not set, the optimal strategy is used
+	// unless there are more drivers or riders than OptimalLimit.
+	Strategy DispatchStrategy
+	// OptimalLimit is the maximal number of drivers or riders assigned with the optimal strategy by default,
+	// 300 will be used if not set.
+	OptimalLimit int
+	// MaxETA is the maximal duration for a driver to reach a rider, there is no limit if not set.
+	MaxETA time.Duration
+	// Distances requests distances along with durations, they are needed for MaxDistance.
+	Distances bool
+	// MaxDistance is the maximal distance in meters for a driver to reach a rider, there is no limit if not set.
+	MaxDistance float64
+	// Eligible reports whether the driver can serve the rider, e.g. has enough seats, all pairs are eligible if not set.
+	Eligible func(driver, rider int) bool
+}
+
+// Assignment represents a driver assigned to a rider
+type Assignment struct {
+	// Driver and Rider are indices of the driver and the rider in the input
+	Driver, Rider int
+	// ETA is the duration in seconds for the driver to reach the rider
+	ETA float64
+	// Distance in meters for the driver to reach the rider, it's set if distances are requested
+	Distance float64
+}
+
+// DispatchResult represents assignments of drivers to riders
+type DispatchResult struct {
+	Strategy DispatchStrategy
+	// Assignments are sorted by rider
+	Assignments []Assignment
+	// UnassignedDrivers and UnassignedRiders are indices of drivers and riders left without a pair
+	UnassignedDrivers, UnassignedRiders []int
+}
+
+// Dispatch assigns drivers to riders by durations from drivers to riders computed with table requests.
+// Every driver serves at most one rider, pairs exceeding ETA or distance limits are never assigned.
+func (o OSRM) Dispatch(ctx context.Context, cfg DispatchConfig, drivers, riders geo.PointSet) (*DispatchResult, error) {
+	durations, distances, err := o.tableMatrices(ctx, cfg.Profile, cfg.MaxTableSize, drivers, riders, cfg.Distances || cfg.MaxDistance > 0)
+	if err != nil {
+		return nil, err
+	}
+	return Assign(cfg, durations, distances), nil
+}
+
+// Assign assigns drivers to riders by ETAs in seconds indexed by driver and rider.
+// Distances are optional, they are needed only if MaxDistance is set.
+// Unreachable pairs are infinite, flatbuffers tables need TableResponse.MarkUnreachable to report them so.
+// Only options of the config affecting the assignment are used.
+func Assign(cfg DispatchConfig, etas, distances [][]float64) *DispatchResult {
+	drivers := len(etas)
+	var riders int
+	if drivers > 0 {
+		riders = len(etas[0])
+	}
+
+	feasible := func(d, r int) bool {
+		eta := etas[d][r]
+		switch {
+		case math.IsInf(eta, 1) || math.IsNaN(eta):
+			return false
+		case cfg.MaxETA > 0 && eta > cfg.MaxETA.Seconds():
+			return false
+		case cfg.MaxDistance > 0 && (distances == nil || distances[d][r] > cfg.MaxDistance):
+			return false
+		case cfg.Eligible != nil && !cfg.Eligible(d, r):
+			return false
+		}
+		return true
+	}
+
+	strategy := cfg.Strategy
+	if strategy == "" {
+		limit := cfg.OptimalLimit
+		if limit <= 0 {
+			limit = defaultDispatchOptimalLimit
+		}
+		strategy = DispatchStrategyOptimal
+		if drivers > limit || riders > limit {
+			strategy = DispatchStrategyGreedy
+		}
+	}
+
+	var pairs [][2]int
+	if strategy == DispatchStrategyGreedy {
+		pairs = assignGreedy(etas, drivers, riders, feasible)
+	} else {
+		pairs = assignOptimal(etas, drivers, riders, feasible)
+	}
+
+	result := &DispatchResult{Strategy: strategy}
+	assignedDrivers := make([]bool, drivers)
+	assignedRiders := make([]bool, riders)
+	for _, p := range pairs {
+		d, r := p[0], p[1]
+		a := Assignment{Driver: d, Rider: r, ETA: etas[d][r]}
+		if distances != nil {
+			a.Distance = distances[d][r]
+		}
+		result.Assignments = append(result.Assignments, a)
+		assignedDrivers[d], assignedRiders[r] = true, true
+	}
+	sort.Slice(result.Assignments, func(i, j int) bool { return result.Assignments[i].Rider < result.Assignments[j].Rider })
+
+	for d, assigned := range assignedDrivers {
+		if !assigned {
+			result.UnassignedDrivers = append(result.UnassignedDrivers, d)
+		}
+	}
+	for r, assigned := range assignedRiders {
+		if !assigned {
+			result.UnassignedRiders = append(result.UnassignedRiders, r)
+		}
+	}
+	return result
+}
+
+// assignGreedy assigns feasible pairs with the smallest ETA first
+func assignGreedy(etas [][]float64, drivers, riders int, feasible func(d, r int) bool) [][2]int {
+	var candidates [][2]int
+	for d := 0; d < drivers; d++ {
+		for r := 0; r < riders; r++ {
+			if feasible(d, r) {
+				candidates = append(candidates, [2]int{d, r})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return etas[candidates[i][0]][candidates[i][1]] < etas[candidates[j][0]][candidates[j][1]]
+	})
+
+	var pairs [][2]int
+	usedDrivers := make([]bool, drivers)
+	usedRiders := make([]bool, riders)
+	for _, c := range candidates {
+		if usedDrivers[c[0]] || usedRiders[c[1]] {
+			continue
+		}
+		usedDrivers[c[0]], usedRiders[c[1]] = true, true
+		pairs = append(pairs, c)
+	}
+	return pairs
+}
+
+// assignOptimal solves the rectangular assignment problem with the Hungarian algorithm.
+// Infeasible pairs cost more than any set of feasible ones, thus the number of feasible pairs is maximized first.
+func assignOptimal(etas [][]float64, drivers, riders int, feasible func(d, r int) bool) [][2]int {
+	if drivers == 0 || riders == 0 {
+		return nil
+	}
+
+	// rows are the smaller side
+	transposed := drivers > riders
+	n, m := drivers, riders
+	if transposed {
+		n, m = riders, drivers
+	}
+	pair := func(row, col int) (d, r int) {
+		if transposed {
+			return col, row
+		}
+		return row, col
+	}
+
+	var maxETA float64
+	for row := 0; row < n; row++ {
+		for col := 0; col < m; col++ {
+			if d, r := pair(row, col); feasible(d, r) && etas[d][r] > maxETA {
+				maxETA = etas[d][r]
+			}
+		}
+	}
+	infeasible := (maxETA + 1) * float64(n+1)
+
+	cost := make([][]float64, n)
+	for row := range cost {
+		cost[row] = make([]float64, m)
+		for col := range cost[row] {
+			cost[row][col] = infeasible
+			if d, r := pair(row, col); feasible(d, r) {
+				cost[row][col] = etas[d][r]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for col, row := range hungarian(cost) {
+		if row < 0 {
+			continue
+		}
+		if d, r := pair(row, col); cost[row][col] < infeasible {
+			pairs = append(pairs, [2]int{d, r})
+		}
+	}
+	return pairs
+}
+
+// hungarian returns the row assigned to every column of the cost matrix with rows not exceeding columns,
+// unassigned columns have -1
+func hungarian(cost [][]float64) []int {
+	n, m := len(cost), len(cost[0])
+	// potentials and the matching use 1-based indices, the column 0 is a fake one
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	rows := make([]int, m)
+	for j := 1; j <= m; j++ {
+		rows[j-1] = p[j] - 1
+	}
+	return rows
+}
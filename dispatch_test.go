@@ -0,0 +1,123 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dispatchLocations returns points along a meridian, a unit is 0.01 degree of latitude or 111 seconds at 10 m/s
+func dispatchLocations(units ...float64) geo.PointSet {
+	ps := make(geo.PointSet, len(units))
+	for i, u := range units {
+		ps[i] = geo.Point{13.388860, 52.517037 + u*0.01}
+	}
+	return ps
+}
+
+func TestDispatchOptimal(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	// the greedy strategy takes the closest pair d1-r0 leaving d0 far away from r1
+	drivers := dispatchLocations(0, 1.8)
+	riders := dispatchLocations(1, 3)
+
+	result, err := NewFromURL(ts.URL).Dispatch(context.Background(), DispatchConfig{Profile: "car", Distances: true}, drivers, riders)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, DispatchStrategyOptimal, result.Strategy)
+	require.Len(t, result.Assignments, 2)
+	assert.Equal(t, 0, result.Assignments[0].Driver)
+	assert.Equal(t, 0, result.Assignments[0].Rider)
+	assert.InDelta(t, 111, result.Assignments[0].ETA, 1)
+	assert.InDelta(t, 1112, result.Assignments[0].Distance, 5)
+	assert.Equal(t, 1, result.Assignments[1].Driver)
+	assert.Equal(t, 1, result.Assignments[1].Rider)
+	assert.Empty(t, result.UnassignedDrivers)
+	assert.Empty(t, result.UnassignedRiders)
+}
+
+func TestDispatchGreedy(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	drivers := dispatchLocations(0, 1.8)
+	riders := dispatchLocations(1, 3)
+
+	result, err := NewFromURL(ts.URL).Dispatch(context.Background(), DispatchConfig{
+		Profile:      "car",
+		OptimalLimit: 1,
+		MaxETA:       4 * time.Minute,
+	}, drivers, riders)
+	require.NoError(t, err)
+	assert.Equal(t, DispatchStrategyGreedy, result.Strategy)
+	require.Len(t, result.Assignments, 1)
+	assert.Equal(t, Assignment{Driver: 1, Rider: 0, ETA: result.Assignments[0].ETA}, result.Assignments[0])
+	assert.InDelta(t, 89, result.Assignments[0].ETA, 1)
+	assert.Equal(t, []int{0}, result.UnassignedDrivers)
+	assert.Equal(t, []int{1}, result.UnassignedRiders)
+}
+
+func TestDispatchConstraints(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 13.5))
+	defer ts.Close()
+
+	drivers := append(dispatchLocations(0, 0.5, 10), geo.Point{13.6, 52.517037})
+	riders := dispatchLocations(0.2, 0.6)
+
+	result, err := NewFromURL(ts.URL).Dispatch(context.Background(), DispatchConfig{
+		Profile:     "car",
+		MaxDistance: 5000,
+		Eligible:    func(driver, rider int) bool { return !(driver == 1 && rider == 1) },
+	}, drivers, riders)
+	require.NoError(t, err)
+	require.Len(t, result.Assignments, 2)
+	assert.Equal(t, 1, result.Assignments[0].Driver)
+	assert.Equal(t, 0, result.Assignments[1].Driver)
+	assert.Equal(t, []int{2, 3}, result.UnassignedDrivers)
+	assert.Empty(t, result.UnassignedRiders)
+}
+
+func TestAssign(t *testing.T) {
+	inf := math.Inf(1)
+	etas := [][]float64{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+		{inf, inf, inf},
+	}
+
+	optimal := Assign(DispatchConfig{}, etas, nil)
+	assert.Equal(t, []Assignment{
+		{Driver: 1, Rider: 0, ETA: 2},
+		{Driver: 0, Rider: 1, ETA: 1},
+		{Driver: 2, Rider: 2, ETA: 2},
+	}, optimal.Assignments)
+	assert.Equal(t, []int{3}, optimal.UnassignedDrivers)
+
+	greedy := Assign(DispatchConfig{Strategy: DispatchStrategyGreedy}, etas, nil)
+	assert.Equal(t, []Assignment{
+		{Driver: 0, Rider: 0, ETA: 4},
+		{Driver: 1, Rider: 1, ETA: 0},
+		{Driver: 2, Rider: 2, ETA: 2},
+	}, greedy.Assignments)
+
+	// the number of assignments goes before the total ETA
+	limited := Assign(DispatchConfig{MaxETA: 3 * time.Second}, [][]float64{{1, 3}, {2, 10}}, nil)
+	assert.Equal(t, []Assignment{
+		{Driver: 1, Rider: 0, ETA: 2},
+		{Driver: 0, Rider: 1, ETA: 3},
+	}, limited.Assignments)
+
+	assert.Empty(t, Assign(DispatchConfig{}, nil, nil).Assignments)
+}
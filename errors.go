@@ -24,3 +24,31 @@ var (
 	ErrNoCoordinates    = errors.New("osrm5: the request should contain coordinates")
 	ErrEmptyServiceName = errors.New("osrm5: the request should contain a service name")
 )
+
+// Invalid response errors
+var (
+	ErrAnnotationLengthMismatch  = errors.New("osrm5: annotations have different number of values")
+	ErrGeometryPrecisionMismatch = errors.New("osrm5: geometry doesn't match the requested geometries precision")
+)
+
+// GPX errors
+var (
+	ErrGPXNoPoints = errors.New("osrm5: GPX contains no track or route points")
+)
+
+// VRP errors
+var (
+	ErrVRPNoVehicles      = errors.New("osrm5: the problem should contain vehicles")
+	ErrVRPInvalidLocation = errors.New("osrm5: location is out of the duration matrix")
+	ErrVRPInvalidPair     = errors.New("osrm5: pair refers to a missing or already paired stop")
+)
+
+// Navigation errors
+var (
+	ErrNoOverviewGeometry = errors.New("osrm5: the route has no overview geometry, request it with full overview")
+)
+
+// Pooling errors
+var (
+	ErrNoFeasibleInsertion = errors.New("osrm5: the ride can't be inserted into the plan within the constraints")
+)
@@ -0,0 +1,197 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// Names of fare quote items, zone items are suffixed with the zone name, e.g. "zone: Airport"
+const (
+	FareItemBase      = "base"
+	FareItemDistance  = "distance"
+	FareItemTime      = "time"
+	FareItemZone      = "zone"
+	FareItemTimeOfDay = "time of day"
+	FareItemMinimum   = "minimum"
+)
+
+const defaultFareUnit = 0.01
+
+// FareSchedule represents prices of trips
+type FareSchedule struct {
+	Currency string
+	// Unit is the smallest unit of the currency amounts are rounded to, 0.01 will be used as default if not set.
+	Unit         float64
+	BaseFare     float64
+	PerKilometer float64
+	PerMinute    float64
+	// MinimumFare is charged if the quote total is lower
+	MinimumFare float64
+	// TimeMultipliers scale the fare by the time of day of the departure, the first matching one is applied
+	TimeMultipliers []FareTimeMultiplier
+	// Zones add surcharges to trips passing through them
+	Zones []FareZone
+}
+
+// FareTimeMultiplier represents a fare multiplier for a period of a day, e.g. night or rush hours
+type FareTimeMultiplier struct {
+	Name string
+	// From and To are wall clock times of the departure location as offsets from midnight,
+	// periods with To before From span midnight
+	From, To   time.Duration
+	Multiplier float64
+}
+
+// FareZone represents an area charged additionally, e.g. an airport or a congestion zone
+type FareZone struct {
+	Name string
+	// Polygon is the boundary of the zone
+	Polygon geo.PointSet
+	// Surcharge is charged once for entering the zone
+	Surcharge float64
+	// PerKilometer is charged additionally for the distance driven within the zone
+	PerKilometer float64
+}
+
+// FareItem represents a line of a fare quote
+type FareItem struct {
+	Name   string
+	Amount float64
+}
+
+// FareTableConfig represents options of table quotes
+type FareTableConfig struct {
+	// Profile is OSRM profile used for table requests.
+	Profile string
+	// MaxTableSize is the maximal number of coordinates in a table request.
+	// OSRM default of 100 coordinates will be used if not set.
+	MaxTableSize int
+}
+
+// FareQuote represents an itemized price of a trip, amounts are rounded to the currency unit
+type FareQuote struct {
+	Currency string
+	// Distance in meters and Duration in seconds of the trip
+	Distance, Duration float64
+	Items              []FareItem
+	Total              float64
+}
+
+// QuoteRoute prices the route departing at the given time.
+// Zone distances are measured along the route geometry using annotations if the route has them.
+// ErrNoOverviewGeometry is returned if the schedule has zones and the route has no geometry to check them.
+func (s FareSchedule) QuoteRoute(r Route, departure time.Time) (FareQuote, error) {
+	if len(s.Zones) > 0 && len(r.Geometry.PointSet) == 0 {
+		return FareQuote{}, ErrNoOverviewGeometry
+	}
+	return s.quote(float64(r.Distance), float64(r.Duration), r.Vertices(), departure), nil
+}
+
+// QuoteMatching prices the matched trip departing at the given time.
+// ErrNoOverviewGeometry is returned if the schedule has zones and the matching has no geometry to check them.
+func (s FareSchedule) QuoteMatching(m Matching, departure time.Time) (FareQuote, error) {
+	r := m.Route
+	if len(m.Geometry.PointSet) > 0 {
+		r.Geometry = m.Geometry
+	}
+	return s.QuoteRoute(r, departure)
+}
+
+// QuoteTable prices trips from every origin to every destination departing at the given time using
+// table distances and durations. Zones are charged by origins and destinations only as there are no geometries.
+// Quotes of unreachable destinations are nil.
+func (o OSRM) QuoteTable(ctx context.Context, cfg FareTableConfig, s FareSchedule, origins, destinations geo.PointSet, departure time.Time) ([][]*FareQuote, error) {
+	durations, distances, err := o.tableMatrices(ctx, cfg.Profile, cfg.MaxTableSize, origins, destinations, true)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([][]*FareQuote, len(origins))
+	for i := range origins {
+		quotes[i] = make([]*FareQuote, len(destinations))
+		for j := range destinations {
+			if math.IsInf(durations[i][j], 1) {
+				continue
+			}
+			vertices := []RouteVertex{
+				{Location: origins[i]},
+				{Location: destinations[j], Distance: distances[i][j], Duration: durations[i][j]},
+			}
+			q := s.quote(distances[i][j], durations[i][j], vertices, departure)
+			quotes[i][j] = &q
+		}
+	}
+	return quotes, nil
+}
+
+func (s FareSchedule) quote(distance, duration float64, vertices []RouteVertex, departure time.Time) FareQuote {
+	q := FareQuote{Currency: s.Currency, Distance: distance, Duration: duration}
+	add := func(name string, amount float64) {
+		if amount = s.round(amount); amount != 0 {
+			q.Items = append(q.Items, FareItem{Name: name, Amount: amount})
+			q.Total = s.round(q.Total + amount)
+		}
+	}
+
+	add(FareItemBase, s.BaseFare)
+	add(FareItemDistance, s.PerKilometer*distance/1000)
+	add(FareItemTime, s.PerMinute*duration/60)
+
+	for _, z := range s.Zones {
+		if entered, inside := zoneDistance(z.Polygon, vertices); entered {
+			add(FareItemZone+": "+z.Name, z.Surcharge+z.PerKilometer*inside/1000)
+		}
+	}
+
+	if m, ok := s.timeMultiplier(departure); ok {
+		add(FareItemTimeOfDay+": "+m.Name, q.Total*(m.Multiplier-1))
+	}
+
+	if q.Total < s.MinimumFare {
+		add(FareItemMinimum, s.MinimumFare-q.Total)
+	}
+	return q
+}
+
+// round rounds the amount to the currency unit
+func (s FareSchedule) round(amount float64) float64 {
+	unit := s.Unit
+	if unit <= 0 {
+		unit = defaultFareUnit
+	}
+	return math.Round(amount/unit) * unit
+}
+
+func (s FareSchedule) timeMultiplier(departure time.Time) (FareTimeMultiplier, bool) {
+	// wall clock time, elapsed time since midnight differs on daylight saving transition days
+	h, m, sec := departure.Clock()
+	t := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second + time.Duration(departure.Nanosecond())
+	for _, m := range s.TimeMultipliers {
+		if (m.From <= m.To && t >= m.From && t < m.To) || (m.From > m.To && (t >= m.From || t < m.To)) {
+			return m, true
+		}
+	}
+	return FareTimeMultiplier{}, false
+}
+
+// zoneDistance checks whether any vertex is inside of the polygon and returns the distance
+// of segments with both ends inside of it
+func zoneDistance(polygon geo.PointSet, vertices []RouteVertex) (entered bool, distance float64) {
+	ring := make([][]float64, len(polygon))
+	for i, p := range polygon {
+		ring[i] = []float64{p.Lng(), p.Lat()}
+	}
+
+	inside := make([]bool, len(vertices))
+	for i, v := range vertices {
+		inside[i] = ringContains(ring, []float64{v.Location.Lng(), v.Location.Lat()})
+		entered = entered || inside[i]
+		if i > 0 && inside[i] && inside[i-1] {
+			distance += v.Distance - vertices[i-1].Distance
+		}
+	}
+	return entered, distance
+}
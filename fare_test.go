@@ -0,0 +1,169 @@
+package osrm
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fareSchedule() FareSchedule {
+	return FareSchedule{
+		Currency:     "EUR",
+		BaseFare:     2.5,
+		PerKilometer: 1.2,
+		PerMinute:    0.3,
+		MinimumFare:  5,
+		TimeMultipliers: []FareTimeMultiplier{
+			{Name: "night", From: 22 * time.Hour, To: 6 * time.Hour, Multiplier: 1.5},
+			{Name: "rush", From: 17 * time.Hour, To: 19 * time.Hour, Multiplier: 1.2},
+		},
+		Zones: []FareZone{{
+			Name: "center",
+			// covers the second and the third route vertices
+			Polygon: geo.PointSet{
+				{13.38, 52.5265}, {13.40, 52.5265}, {13.40, 52.5375}, {13.38, 52.5375}, {13.38, 52.5265},
+			},
+			Surcharge:    3,
+			PerKilometer: 0.5,
+		}},
+	}
+}
+
+func fareRoute() Route {
+	return Route{
+		Distance: 3000,
+		Duration: 300,
+		Geometry: NewGeometryFromPointSet(dispatchLocations(0, 1, 2, 3)),
+		Legs: []RouteLeg{{Annotation: Annotation{
+			Distance: []float32{1000, 1000, 1000},
+			Duration: []float32{100, 100, 100},
+		}}},
+	}
+}
+
+func TestQuoteRoute(t *testing.T) {
+	q, err := fareSchedule().QuoteRoute(fareRoute(), time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", q.Currency)
+	assert.Equal(t, 3000.0, q.Distance)
+	assert.Equal(t, 300.0, q.Duration)
+	require.Len(t, q.Items, 4)
+	assert.Equal(t, FareItem{Name: FareItemBase, Amount: 2.5}, q.Items[0])
+	assert.Equal(t, FareItemDistance, q.Items[1].Name)
+	assert.InDelta(t, 3.6, q.Items[1].Amount, 1e-9)
+	assert.Equal(t, FareItem{Name: FareItemTime, Amount: 1.5}, q.Items[2])
+	assert.Equal(t, "zone: center", q.Items[3].Name)
+	assert.InDelta(t, 3.5, q.Items[3].Amount, 1e-9)
+	assert.InDelta(t, 11.1, q.Total, 1e-9)
+}
+
+func TestQuoteRouteTimeOfDay(t *testing.T) {
+	s := fareSchedule()
+
+	night, err := s.QuoteRoute(fareRoute(), time.Date(2021, 6, 1, 23, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, night.Items, 5)
+	assert.Equal(t, "time of day: night", night.Items[4].Name)
+	assert.InDelta(t, 5.55, night.Items[4].Amount, 1e-9)
+	assert.InDelta(t, 16.65, night.Total, 1e-9)
+
+	early, err := s.QuoteRoute(fareRoute(), time.Date(2021, 6, 1, 5, 59, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.InDelta(t, 16.65, early.Total, 1e-9)
+
+	rush, err := s.QuoteRoute(fareRoute(), time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.InDelta(t, 13.32, rush.Total, 1e-9)
+
+	evening, err := s.QuoteRoute(fareRoute(), time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.InDelta(t, 11.1, evening.Total, 1e-9)
+}
+
+func TestQuoteMinimumFare(t *testing.T) {
+	r := Route{Distance: 500, Duration: 60, Geometry: NewGeometryFromPointSet(dispatchLocations(-1, -0.95))}
+	q, err := fareSchedule().QuoteRoute(r, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Len(t, q.Items, 4)
+	assert.Equal(t, FareItemMinimum, q.Items[3].Name)
+	assert.InDelta(t, 1.6, q.Items[3].Amount, 1e-9)
+	assert.InDelta(t, 5, q.Total, 1e-9)
+}
+
+func TestQuoteMatching(t *testing.T) {
+	m := Matching{Route: fareRoute()}
+	m.Route.Geometry = Geometry{}
+	m.Geometry = fareRoute().Geometry
+
+	q, err := fareSchedule().QuoteMatching(m, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.InDelta(t, 11.1, q.Total, 1e-9)
+
+	// zones can't be charged without the geometry
+	m.Geometry = Geometry{}
+	_, err = fareSchedule().QuoteMatching(m, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, ErrNoOverviewGeometry, err)
+}
+
+func TestQuoteRouteRounding(t *testing.T) {
+	s := FareSchedule{PerKilometer: 1.234}
+	r := Route{Distance: 1000, Duration: 60}
+	q, err := s.QuoteRoute(r, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []FareItem{{Name: FareItemDistance, Amount: 1.23}}, q.Items)
+	assert.Equal(t, 1.23, q.Total)
+
+	s.Unit = 0.5
+	q, err = s.QuoteRoute(r, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, q.Total)
+}
+
+func TestQuoteRouteTimeOfDayOnDaylightSavingTransition(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database isn't available")
+	}
+
+	// clocks are moved forward by an hour on the night before
+	q, err := fareSchedule().QuoteRoute(fareRoute(), time.Date(2021, 3, 28, 17, 30, 0, 0, berlin))
+	require.NoError(t, err)
+	assert.Equal(t, "time of day: rush", q.Items[len(q.Items)-1].Name)
+}
+
+func TestQuoteTable(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 13.5))
+	defer ts.Close()
+
+	origins := dispatchLocations(0)
+	destinations := append(dispatchLocations(3, 1.5), geo.Point{13.6, 52.517037})
+	quotes, err := NewFromURL(ts.URL).QuoteTable(context.Background(), FareTableConfig{Profile: "car"}, fareSchedule(), origins, destinations, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	require.Len(t, quotes, 1)
+	require.Len(t, quotes[0], 3)
+	require.NotNil(t, quotes[0][0])
+	assert.InDelta(t, 3340, quotes[0][0].Distance, 5)
+	assert.InDelta(t, 334, quotes[0][0].Duration, 1)
+	// the second destination is in the zone
+	require.NotNil(t, quotes[0][1])
+	assert.Equal(t, "zone: center", quotes[0][1].Items[3].Name)
+	assert.Equal(t, 3.0, quotes[0][1].Items[3].Amount)
+	assert.Nil(t, quotes[0][2])
+
+	// a request per destination fits the table size
+	requests = 0
+	limited, err := NewFromURL(ts.URL).QuoteTable(context.Background(), FareTableConfig{Profile: "car", MaxTableSize: 2}, fareSchedule(), origins, destinations, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, quotes, limited)
+}
@@ -0,0 +1,642 @@
+package osrm
+
+import (
+	"fmt"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	geo "github.com/paulmach/go.geo"
+)
+
+// Field slots of the OSRM flatbuffers schema.
+// See https://github.com/Project-OSRM/osrm-backend/tree/master/include/engine/api/flatbuffers for details.
+const (
+	fbResultError = iota
+	fbResultCode
+	fbResultDataVersion
+	fbResultWaypoints
+	fbResultRoutes
+	fbResultTable
+)
+
+const (
+	fbErrorCode = iota
+	fbErrorMessage
+)
+
+const (
+	fbWaypointHint = iota
+	fbWaypointDistance
+	fbWaypointName
+	fbWaypointLocation
+	fbWaypointNodes
+	fbWaypointMatchingsIndex
+	fbWaypointWaypointIndex
+	fbWaypointAlternativesCount
+	fbWaypointTripsIndex
+)
+
+const (
+	fbRouteDistance = iota
+	fbRouteDuration
+	fbRouteWeight
+	fbRouteWeightName
+	fbRouteConfidence
+	fbRoutePolyline
+	fbRouteCoordinates
+	fbRouteLegs
+)
+
+const (
+	fbLegDistance = iota
+	fbLegDuration
+	fbLegWeight
+	fbLegSummary
+	fbLegAnnotations
+	fbLegSteps
+)
+
+const (
+	fbAnnotationDistance = iota
+	fbAnnotationDuration
+	fbAnnotationDatasources
+	fbAnnotationNodes
+	fbAnnotationWeight
+	fbAnnotationSpeed
+	fbAnnotationMetadata
+)
+
+const (
+	fbMetadataDatasourceNames = iota
+)
+
+const (
+	fbStepDistance = iota
+	fbStepDuration
+	fbStepPolyline
+	fbStepCoordinates
+	fbStepWeight
+	fbStepName
+	fbStepRef
+	fbStepPronunciation
+	fbStepDestinations
+	fbStepExits
+	fbStepMode
+	fbStepManeuver
+	fbStepIntersections
+	fbStepRotaryName
+	fbStepRotaryPronunciation
+	fbStepDrivingSide
+)
+
+const (
+	fbManeuverLocation = iota
+	fbManeuverBearingBefore
+	fbManeuverBearingAfter
+	fbManeuverType
+	fbManeuverModifier
+	fbManeuverExit
+)
+
+const (
+	fbIntersectionLocation = iota
+	fbIntersectionBearings
+	fbIntersectionClasses
+	fbIntersectionEntry
+	fbIntersectionInBearing
+	fbIntersectionOutBearing
+	fbIntersectionLanes
+)
+
+const (
+	fbLaneIndications = iota
+	fbLaneValid
+)
+
+const (
+	fbTableDurations = iota
+	fbTableRows
+	fbTableCols
+	fbTableDistances
+	fbTableDestinations
+	fbTableFallbackSpeedCells
+)
+
+// fbManeuverTypes maps ManeuverType flatbuffers enum to its JSON representation
+var fbManeuverTypes = []string{
+	ManeuverTypeTurn.String(),
+	ManeuverTypeNewName.String(),
+	ManeuverTypeDepart.String(),
+	ManeuverTypeArrive.String(),
+	ManeuverTypeMerge.String(),
+	ManeuverTypeOnRamp.String(),
+	ManeuverTypeOffRamp.String(),
+	ManeuverTypeFork.String(),
+	ManeuverTypeEndOfRoad.String(),
+	ManeuverTypeContinue.String(),
+	ManeuverTypeRoundabout.String(),
+	ManeuverTypeRotary.String(),
+	ManeuverTypeRoundaboutTurn.String(),
+	ManeuverTypeNotification.String(),
+	ManeuverTypeExitRoundabout.String(),
+	ManeuverTypeExitRotary.String(),
+}
+
+// fbTurns maps Turn flatbuffers enum to its JSON representation,
+// it is shared by maneuver modifiers and lane indications
+var fbTurns = []string{
+	LaneIndicationNone.String(),
+	LaneIndicationUTurn.String(),
+	LaneIndicationSharpRight.String(),
+	LaneIndicationRight.String(),
+	LaneIndicationSlightRight.String(),
+	LaneIndicationStraight.String(),
+	LaneIndicationSlightLeft.String(),
+	LaneIndicationLeft.String(),
+	LaneIndicationSharpLeft.String(),
+}
+
+// flatbuffersUnmarshaler is implemented by responses which can be decoded from flatbuffers format
+type flatbuffersUnmarshaler interface {
+	unmarshalFlatbuffers(root fbTable)
+}
+
+// unmarshalFlatbuffers decodes OSRM FBResult into the given response
+func unmarshalFlatbuffers(b []byte, out interface{}) (err error) {
+	u, ok := out.(flatbuffersUnmarshaler)
+	if !ok {
+		return fmt.Errorf("flatbuffers format is not supported for %T", out)
+	}
+	if len(b) < flatbuffers.SizeUOffsetT {
+		return fmt.Errorf("failed to decode flatbuffers body: too short buffer of %d bytes", len(b))
+	}
+
+	// flatbuffers accessors don't validate a buffer and panic on malformed data
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to decode flatbuffers body: %v", r)
+		}
+	}()
+
+	u.unmarshalFlatbuffers(fbTable{flatbuffers.Table{
+		Bytes: b,
+		Pos:   flatbuffers.GetUOffsetT(b),
+	}})
+	return nil
+}
+
+// fbTable provides access to flatbuffers table fields by their slot numbers
+type fbTable struct {
+	flatbuffers.Table
+}
+
+func (t fbTable) offset(slot int) flatbuffers.UOffsetT {
+	return flatbuffers.UOffsetT(t.Offset(flatbuffers.VOffsetT(flatbuffers.VtableMetadataFields+slot) * flatbuffers.SizeVOffsetT))
+}
+
+func (t fbTable) has(slot int) bool {
+	return t.offset(slot) != 0
+}
+
+func (t fbTable) table(slot int) (fbTable, bool) {
+	o := t.offset(slot)
+	if o == 0 {
+		return fbTable{}, false
+	}
+	return fbTable{flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(o + t.Pos)}}, true
+}
+
+func (t fbTable) string(slot int) string {
+	if o := t.offset(slot); o != 0 {
+		return t.String(o + t.Pos)
+	}
+	return ""
+}
+
+func (t fbTable) bool(slot int) bool {
+	if o := t.offset(slot); o != 0 {
+		return t.GetBool(o + t.Pos)
+	}
+	return false
+}
+
+func (t fbTable) uint8(slot int) uint8 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetUint8(o + t.Pos)
+	}
+	return 0
+}
+
+func (t fbTable) int8(slot int) int8 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetInt8(o + t.Pos)
+	}
+	return 0
+}
+
+func (t fbTable) uint16(slot int) uint16 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetUint16(o + t.Pos)
+	}
+	return 0
+}
+
+func (t fbTable) uint32(slot int) uint32 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetUint32(o + t.Pos)
+	}
+	return 0
+}
+
+func (t fbTable) float32(slot int) float32 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetFloat32(o + t.Pos)
+	}
+	return 0
+}
+
+func (t fbTable) float64(slot int) float64 {
+	if o := t.offset(slot); o != 0 {
+		return t.GetFloat64(o + t.Pos)
+	}
+	return 0
+}
+
+// position reads Position struct {longitude: float; latitude: float} stored inline
+func (t fbTable) position(slot int) geo.Point {
+	o := t.offset(slot)
+	if o == 0 {
+		return geo.Point{}
+	}
+	pos := o + t.Pos
+	return geo.Point{
+		float64(t.GetFloat32(pos)),
+		float64(t.GetFloat32(pos + flatbuffers.SizeFloat32)),
+	}
+}
+
+// vector returns start and length of a vector field
+func (t fbTable) vector(slot int) (flatbuffers.UOffsetT, int) {
+	o := t.offset(slot)
+	if o == 0 {
+		return 0, 0
+	}
+	return t.Vector(o), t.VectorLen(o)
+}
+
+func (t fbTable) tables(slot int) []fbTable {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]fbTable, n)
+	for i := range out {
+		x := start + flatbuffers.UOffsetT(i*flatbuffers.SizeUOffsetT)
+		out[i] = fbTable{flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(x)}}
+	}
+	return out
+}
+
+func (t fbTable) strings(slot int) []string {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]string, n)
+	for i := range out {
+		out[i] = t.String(start + flatbuffers.UOffsetT(i*flatbuffers.SizeUOffsetT))
+	}
+	return out
+}
+
+func (t fbTable) bools(slot int) []bool {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = t.GetBool(start + flatbuffers.UOffsetT(i*flatbuffers.SizeBool))
+	}
+	return out
+}
+
+func (t fbTable) int8s(slot int) []int8 {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]int8, n)
+	for i := range out {
+		out[i] = t.GetInt8(start + flatbuffers.UOffsetT(i*flatbuffers.SizeInt8))
+	}
+	return out
+}
+
+func (t fbTable) int16s(slot int) []int16 {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = t.GetInt16(start + flatbuffers.UOffsetT(i*flatbuffers.SizeInt16))
+	}
+	return out
+}
+
+func (t fbTable) uint32s(slot int) []uint32 {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]uint32, n)
+	for i := range out {
+		out[i] = t.GetUint32(start + flatbuffers.UOffsetT(i*flatbuffers.SizeUint32))
+	}
+	return out
+}
+
+func (t fbTable) float32s(slot int) []float32 {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make([]float32, n)
+	for i := range out {
+		out[i] = t.GetFloat32(start + flatbuffers.UOffsetT(i*flatbuffers.SizeFloat32))
+	}
+	return out
+}
+
+func (t fbTable) positions(slot int) geo.PointSet {
+	start, n := t.vector(slot)
+	if n == 0 {
+		return nil
+	}
+	out := make(geo.PointSet, n)
+	for i := range out {
+		pos := start + flatbuffers.UOffsetT(i*2*flatbuffers.SizeFloat32)
+		out[i] = geo.Point{
+			float64(t.GetFloat32(pos)),
+			float64(t.GetFloat32(pos + flatbuffers.SizeFloat32)),
+		}
+	}
+	return out
+}
+
+// geometry reads either an encoded polyline or a coordinates vector
+func (t fbTable) geometry(polylineSlot, coordinatesSlot int) Geometry {
+	if encoded := t.string(polylineSlot); encoded != "" {
+		return NewGeometryFromPath(*geo.NewPathFromEncoding(encoded, polyline6Factor))
+	}
+	g := NewGeometryFromPointSet(t.positions(coordinatesSlot))
+	g.Encoding = GeometriesGeojson
+	return g
+}
+
+func (r *ResponseStatus) unmarshalFlatbuffers(root fbTable) {
+	r.Code = errorCodeOK
+	r.Message = ""
+	r.DataVersion = root.string(fbResultDataVersion)
+	if !root.bool(fbResultError) {
+		return
+	}
+	if code, ok := root.table(fbResultCode); ok {
+		r.Code = code.string(fbErrorCode)
+		r.Message = code.string(fbErrorMessage)
+	}
+}
+
+func (r *RouteResponse) unmarshalFlatbuffers(root fbTable) {
+	r.ResponseStatus.unmarshalFlatbuffers(root)
+
+	r.Waypoints = nil
+	for _, t := range root.tables(fbResultWaypoints) {
+		r.Waypoints = append(r.Waypoints, Waypoint{
+			Name:     t.string(fbWaypointName),
+			Location: t.position(fbWaypointLocation),
+			Distance: t.float32(fbWaypointDistance),
+			Hint:     t.string(fbWaypointHint),
+		})
+	}
+
+	r.Routes = nil
+	for _, t := range root.tables(fbResultRoutes) {
+		r.Routes = append(r.Routes, fbRoute(t))
+	}
+}
+
+func (r *TableResponse) unmarshalFlatbuffers(root fbTable) {
+	r.ResponseStatus.unmarshalFlatbuffers(root)
+
+	r.Durations, r.Distances = nil, nil
+	t, ok := root.table(fbResultTable)
+	if !ok {
+		return
+	}
+	rows, cols := int(t.uint16(fbTableRows)), int(t.uint16(fbTableCols))
+	r.Durations = fbMatrix(t.float32s(fbTableDurations), rows, cols, "durations")
+	r.Distances = fbMatrix(t.float32s(fbTableDistances), rows, cols, "distances")
+}
+
+// fbMatrix splits flattened table values into rows, missing values result in nil matrix
+func fbMatrix(values []float32, rows, cols int, name string) [][]float32 {
+	if len(values) == 0 {
+		return nil
+	}
+	if rows*cols != len(values) {
+		panic(fmt.Sprintf("table of %dx%d has %d %s", rows, cols, len(values), name))
+	}
+	matrix := make([][]float32, rows)
+	for i := range matrix {
+		matrix[i] = values[i*cols : (i+1)*cols]
+	}
+	return matrix
+}
+
+func (r *MatchResponse) unmarshalFlatbuffers(root fbTable) {
+	r.ResponseStatus.unmarshalFlatbuffers(root)
+
+	r.Tracepoints = nil
+	for _, t := range root.tables(fbResultWaypoints) {
+		// OSRM writes empty waypoints for the trace points which were not matched
+		if !t.has(fbWaypointLocation) {
+			r.Tracepoints = append(r.Tracepoints, nil)
+			continue
+		}
+		r.Tracepoints = append(r.Tracepoints, &Tracepoint{
+			Index:             int(t.uint32(fbWaypointWaypointIndex)),
+			Location:          t.position(fbWaypointLocation),
+			MatchingIndex:     int(t.uint32(fbWaypointMatchingsIndex)),
+			AlternativesCount: int(t.uint32(fbWaypointAlternativesCount)),
+			Hint:              t.string(fbWaypointHint),
+			Name:              t.string(fbWaypointName),
+			Distance:          float64(t.float32(fbWaypointDistance)),
+		})
+	}
+
+	r.Matchings = nil
+	for _, t := range root.tables(fbResultRoutes) {
+		route := fbRoute(t)
+		r.Matchings = append(r.Matchings, Matching{
+			Route:      route,
+			Confidence: float64(t.float32(fbRouteConfidence)),
+			Geometry:   route.Geometry,
+		})
+	}
+}
+
+func (r *NearestResponse) unmarshalFlatbuffers(root fbTable) {
+	r.ResponseStatus.unmarshalFlatbuffers(root)
+
+	r.Waypoints = nil
+	for _, t := range root.tables(fbResultWaypoints) {
+		wp := NearestWaypoint{
+			Location: t.position(fbWaypointLocation),
+			Distance: float64(t.float32(fbWaypointDistance)),
+			Name:     t.string(fbWaypointName),
+			Hint:     t.string(fbWaypointHint),
+		}
+		// nodes is Uint64Pair struct {first: ulong; second: ulong} stored inline
+		if o := t.offset(fbWaypointNodes); o != 0 {
+			pos := o + t.Pos
+			wp.Nodes = []uint64{t.GetUint64(pos), t.GetUint64(pos + flatbuffers.SizeUint64)}
+		}
+		r.Waypoints = append(r.Waypoints, wp)
+	}
+}
+
+func fbRoute(t fbTable) Route {
+	route := Route{
+		Distance:   t.float32(fbRouteDistance),
+		Duration:   t.float32(fbRouteDuration),
+		WeightName: t.string(fbRouteWeightName),
+		Wieght:     t.float32(fbRouteWeight),
+		Geometry:   t.geometry(fbRoutePolyline, fbRouteCoordinates),
+	}
+	for _, l := range t.tables(fbRouteLegs) {
+		route.Legs = append(route.Legs, fbRouteLeg(l))
+	}
+	return route
+}
+
+func fbRouteLeg(t fbTable) RouteLeg {
+	leg := RouteLeg{
+		Distance: float32(t.float64(fbLegDistance)),
+		Duration: float32(t.float64(fbLegDuration)),
+		Summary:  t.string(fbLegSummary),
+		Weight:   float32(t.float64(fbLegWeight)),
+	}
+	if a, ok := t.table(fbLegAnnotations); ok {
+		leg.Annotation = fbAnnotation(a)
+	}
+	for _, s := range t.tables(fbLegSteps) {
+		leg.Steps = append(leg.Steps, fbRouteStep(s))
+	}
+	return leg
+}
+
+func fbAnnotation(t fbTable) Annotation {
+	var a Annotation
+	for _, v := range t.uint32s(fbAnnotationDuration) {
+		a.Duration = append(a.Duration, float32(v))
+	}
+	for _, v := range t.uint32s(fbAnnotationDistance) {
+		a.Distance = append(a.Distance, float32(v))
+	}
+	for _, v := range t.uint32s(fbAnnotationNodes) {
+		a.Nodes = append(a.Nodes, uint64(v))
+	}
+	for _, v := range t.uint32s(fbAnnotationWeight) {
+		a.Weight = append(a.Weight, float32(v))
+	}
+	a.Speed = t.float32s(fbAnnotationSpeed)
+	a.Datasources = t.uint32s(fbAnnotationDatasources)
+	if m, ok := t.table(fbAnnotationMetadata); ok {
+		a.Metadata = &AnnotationMetadata{DatasourceNames: m.strings(fbMetadataDatasourceNames)}
+	}
+	if err := a.Validate(); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func fbRouteStep(t fbTable) RouteStep {
+	step := RouteStep{
+		Distance:    t.float32(fbStepDistance),
+		Duration:    t.float32(fbStepDuration),
+		Geometry:    t.geometry(fbStepPolyline, fbStepCoordinates),
+		Name:        t.string(fbStepName),
+		Ref:         t.string(fbStepRef),
+		Mode:        TravelMode(t.string(fbStepMode)),
+		DrivingSide: "right",
+		Weight:      t.float32(fbStepWeight),
+
+		Pronunciation:       t.string(fbStepPronunciation),
+		Destinations:        t.string(fbStepDestinations),
+		Exits:               t.string(fbStepExits),
+		RotaryName:          t.string(fbStepRotaryName),
+		RotaryPronunciation: t.string(fbStepRotaryPronunciation),
+	}
+	if t.bool(fbStepDrivingSide) {
+		step.DrivingSide = "left"
+	}
+	if m, ok := t.table(fbStepManeuver); ok {
+		step.Maneuver = fbManeuver(m)
+	}
+	for _, i := range t.tables(fbStepIntersections) {
+		step.Intersections = append(step.Intersections, fbIntersection(i))
+	}
+	return step
+}
+
+func fbManeuver(t fbTable) StepManeuver {
+	m := StepManeuver{
+		Location:      t.position(fbManeuverLocation),
+		BearingBefore: float32(t.uint16(fbManeuverBearingBefore)),
+		BearingAfter:  float32(t.uint16(fbManeuverBearingAfter)),
+		Type:          ManeuverType(fbEnum(fbManeuverTypes, t.int8(fbManeuverType))),
+	}
+	if modifier := t.int8(fbManeuverModifier); modifier != 0 {
+		m.Modifier = ManeuverModifier(fbEnum(fbTurns, modifier))
+	}
+	if exit := uint32(t.uint8(fbManeuverExit)); exit != 0 {
+		m.Exit = &exit
+	}
+	return m
+}
+
+func fbIntersection(t fbTable) Intersection {
+	in := Intersection{
+		Location: t.position(fbIntersectionLocation),
+		Classes:  t.strings(fbIntersectionClasses),
+		Entry:    t.bools(fbIntersectionEntry),
+	}
+	for _, b := range t.int16s(fbIntersectionBearings) {
+		in.Bearings = append(in.Bearings, uint16(b))
+	}
+	if t.has(fbIntersectionInBearing) {
+		v := t.uint32(fbIntersectionInBearing)
+		in.In = &v
+	}
+	if t.has(fbIntersectionOutBearing) {
+		v := t.uint32(fbIntersectionOutBearing)
+		in.Out = &v
+	}
+	for _, l := range t.tables(fbIntersectionLanes) {
+		lane := Lane{Valid: l.bool(fbLaneValid)}
+		for _, i := range l.int8s(fbLaneIndications) {
+			lane.Indications = append(lane.Indications, LaneIndication(fbEnum(fbTurns, i)))
+		}
+		in.Lanes = append(in.Lanes, lane)
+	}
+	return in
+}
+
+// fbEnum returns the name of an enum value, values unknown to the schema, e.g. added by a newer server, decode to empty
+func fbEnum(names []string, v int8) string {
+	if v < 0 || int(v) >= len(names) {
+		return ""
+	}
+	return names[v]
+}
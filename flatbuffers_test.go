@@ -0,0 +1,624 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fbEncoder builds OSRM FBResult buffers from responses for tests and benchmarks
+type fbEncoder struct {
+	*flatbuffers.Builder
+}
+
+func newFBEncoder() fbEncoder {
+	return fbEncoder{flatbuffers.NewBuilder(1024)}
+}
+
+func (e fbEncoder) finish(status ResponseStatus, waypoints, routes []flatbuffers.UOffsetT, table flatbuffers.UOffsetT) []byte {
+	var code flatbuffers.UOffsetT
+	if status.Code != errorCodeOK {
+		c, m := e.CreateString(status.Code), e.CreateString(status.Message)
+		e.StartObject(2)
+		e.PrependUOffsetTSlot(fbErrorCode, c, 0)
+		e.PrependUOffsetTSlot(fbErrorMessage, m, 0)
+		code = e.EndObject()
+	}
+	dataVersion := e.CreateString(status.DataVersion)
+	wps, rs := e.offsets(waypoints), e.offsets(routes)
+
+	e.StartObject(6)
+	e.PrependBoolSlot(fbResultError, status.Code != errorCodeOK, false)
+	e.PrependUOffsetTSlot(fbResultCode, code, 0)
+	e.PrependUOffsetTSlot(fbResultDataVersion, dataVersion, 0)
+	e.PrependUOffsetTSlot(fbResultWaypoints, wps, 0)
+	e.PrependUOffsetTSlot(fbResultRoutes, rs, 0)
+	e.PrependUOffsetTSlot(fbResultTable, table, 0)
+	e.Finish(e.EndObject())
+	return e.FinishedBytes()
+}
+
+func (e fbEncoder) offsets(v []flatbuffers.UOffsetT) flatbuffers.UOffsetT {
+	if v == nil {
+		return 0
+	}
+	e.StartVector(flatbuffers.SizeUOffsetT, len(v), flatbuffers.SizeUOffsetT)
+	for i := len(v) - 1; i >= 0; i-- {
+		e.PrependUOffsetT(v[i])
+	}
+	return e.EndVector(len(v))
+}
+
+func (e fbEncoder) float32s(v []float32) flatbuffers.UOffsetT {
+	e.StartVector(flatbuffers.SizeFloat32, len(v), flatbuffers.SizeFloat32)
+	for i := len(v) - 1; i >= 0; i-- {
+		e.PrependFloat32(v[i])
+	}
+	return e.EndVector(len(v))
+}
+
+func (e fbEncoder) uint32s(v []uint32) flatbuffers.UOffsetT {
+	e.StartVector(flatbuffers.SizeUint32, len(v), flatbuffers.SizeUint32)
+	for i := len(v) - 1; i >= 0; i-- {
+		e.PrependUint32(v[i])
+	}
+	return e.EndVector(len(v))
+}
+
+func (e fbEncoder) position(slot int, p geo.Point) {
+	e.Prep(flatbuffers.SizeFloat32, 2*flatbuffers.SizeFloat32)
+	e.PrependFloat32(float32(p.Lat()))
+	e.PrependFloat32(float32(p.Lng()))
+	e.PrependStructSlot(slot, e.Offset(), 0)
+}
+
+func (e fbEncoder) waypoint(hint, name string, location geo.Point, distance float32, extra func()) flatbuffers.UOffsetT {
+	h, n := e.CreateString(hint), e.CreateString(name)
+	e.StartObject(9)
+	e.PrependUOffsetTSlot(fbWaypointHint, h, 0)
+	e.PrependFloat32Slot(fbWaypointDistance, distance, 0)
+	e.PrependUOffsetTSlot(fbWaypointName, n, 0)
+	e.position(fbWaypointLocation, location)
+	if extra != nil {
+		extra()
+	}
+	return e.EndObject()
+}
+
+func (e fbEncoder) route(r Route, confidence float64) flatbuffers.UOffsetT {
+	var legs []flatbuffers.UOffsetT
+	for _, l := range r.Legs {
+		legs = append(legs, e.leg(l))
+	}
+	ls := e.offsets(legs)
+	weightName := e.CreateString(r.WeightName)
+	polyline := e.CreateString(r.Geometry.Polyline(polyline6Factor))
+
+	e.StartObject(8)
+	e.PrependFloat32Slot(fbRouteDistance, r.Distance, 0)
+	e.PrependFloat32Slot(fbRouteDuration, r.Duration, 0)
+	e.PrependFloat32Slot(fbRouteWeight, r.Wieght, 0)
+	e.PrependUOffsetTSlot(fbRouteWeightName, weightName, 0)
+	e.PrependFloat32Slot(fbRouteConfidence, float32(confidence), 0)
+	e.PrependUOffsetTSlot(fbRoutePolyline, polyline, 0)
+	e.PrependUOffsetTSlot(fbRouteLegs, ls, 0)
+	return e.EndObject()
+}
+
+func (e fbEncoder) leg(l RouteLeg) flatbuffers.UOffsetT {
+	var steps []flatbuffers.UOffsetT
+	for _, s := range l.Steps {
+		steps = append(steps, e.step(s))
+	}
+	ss := e.offsets(steps)
+	summary := e.CreateString(l.Summary)
+
+	var duration, distance, nodes []uint32
+	for _, v := range l.Annotation.Duration {
+		duration = append(duration, uint32(v))
+	}
+	for _, v := range l.Annotation.Distance {
+		distance = append(distance, uint32(v))
+	}
+	for _, v := range l.Annotation.Nodes {
+		nodes = append(nodes, uint32(v))
+	}
+	var weight []uint32
+	for _, v := range l.Annotation.Weight {
+		weight = append(weight, uint32(v))
+	}
+	du, di, no := e.uint32s(duration), e.uint32s(distance), e.uint32s(nodes)
+	we, sp, ds := e.uint32s(weight), e.float32s(l.Annotation.Speed), e.uint32s(l.Annotation.Datasources)
+	var metadata flatbuffers.UOffsetT
+	if l.Annotation.Metadata != nil {
+		var names []flatbuffers.UOffsetT
+		for _, n := range l.Annotation.Metadata.DatasourceNames {
+			names = append(names, e.CreateString(n))
+		}
+		ns := e.offsets(names)
+		e.StartObject(1)
+		e.PrependUOffsetTSlot(fbMetadataDatasourceNames, ns, 0)
+		metadata = e.EndObject()
+	}
+	e.StartObject(7)
+	e.PrependUOffsetTSlot(fbAnnotationDuration, du, 0)
+	e.PrependUOffsetTSlot(fbAnnotationDistance, di, 0)
+	e.PrependUOffsetTSlot(fbAnnotationNodes, no, 0)
+	e.PrependUOffsetTSlot(fbAnnotationWeight, we, 0)
+	e.PrependUOffsetTSlot(fbAnnotationSpeed, sp, 0)
+	e.PrependUOffsetTSlot(fbAnnotationDatasources, ds, 0)
+	e.PrependUOffsetTSlot(fbAnnotationMetadata, metadata, 0)
+	annotation := e.EndObject()
+
+	e.StartObject(6)
+	e.PrependFloat64Slot(fbLegDistance, float64(l.Distance), 0)
+	e.PrependFloat64Slot(fbLegDuration, float64(l.Duration), 0)
+	e.PrependFloat64Slot(fbLegWeight, float64(l.Weight), 0)
+	e.PrependUOffsetTSlot(fbLegSummary, summary, 0)
+	e.PrependUOffsetTSlot(fbLegAnnotations, annotation, 0)
+	e.PrependUOffsetTSlot(fbLegSteps, ss, 0)
+	return e.EndObject()
+}
+
+func (e fbEncoder) step(s RouteStep) flatbuffers.UOffsetT {
+	var intersections []flatbuffers.UOffsetT
+	for _, in := range s.Intersections {
+		intersections = append(intersections, e.intersection(in))
+	}
+	is := e.offsets(intersections)
+	polyline := e.CreateString(s.Geometry.Polyline(polyline6Factor))
+	name, mode := e.CreateString(s.Name), e.CreateString(s.Mode.String())
+	ref, pronunciation := e.CreateString(s.Ref), e.CreateString(s.Pronunciation)
+	destinations, exits := e.CreateString(s.Destinations), e.CreateString(s.Exits)
+	rotaryName, rotaryPronunciation := e.CreateString(s.RotaryName), e.CreateString(s.RotaryPronunciation)
+
+	e.StartObject(6)
+	e.position(fbManeuverLocation, s.Maneuver.Location)
+	e.PrependUint16Slot(fbManeuverBearingBefore, uint16(s.Maneuver.BearingBefore), 0)
+	e.PrependUint16Slot(fbManeuverBearingAfter, uint16(s.Maneuver.BearingAfter), 0)
+	e.PrependInt8Slot(fbManeuverType, fbEnumValue(fbManeuverTypes, s.Maneuver.Type.String()), 0)
+	e.PrependInt8Slot(fbManeuverModifier, fbEnumValue(fbTurns, s.Maneuver.Modifier.String()), 0)
+	if s.Maneuver.Exit != nil {
+		e.PrependUint8Slot(fbManeuverExit, uint8(*s.Maneuver.Exit), 0)
+	}
+	maneuver := e.EndObject()
+
+	e.StartObject(16)
+	e.PrependFloat32Slot(fbStepDistance, s.Distance, 0)
+	e.PrependFloat32Slot(fbStepDuration, s.Duration, 0)
+	e.PrependUOffsetTSlot(fbStepPolyline, polyline, 0)
+	e.PrependFloat32Slot(fbStepWeight, s.Weight, 0)
+	e.PrependUOffsetTSlot(fbStepName, name, 0)
+	e.PrependUOffsetTSlot(fbStepMode, mode, 0)
+	e.PrependUOffsetTSlot(fbStepRef, ref, 0)
+	e.PrependUOffsetTSlot(fbStepPronunciation, pronunciation, 0)
+	e.PrependUOffsetTSlot(fbStepDestinations, destinations, 0)
+	e.PrependUOffsetTSlot(fbStepExits, exits, 0)
+	e.PrependUOffsetTSlot(fbStepRotaryName, rotaryName, 0)
+	e.PrependUOffsetTSlot(fbStepRotaryPronunciation, rotaryPronunciation, 0)
+	e.PrependUOffsetTSlot(fbStepManeuver, maneuver, 0)
+	e.PrependUOffsetTSlot(fbStepIntersections, is, 0)
+	e.PrependBoolSlot(fbStepDrivingSide, s.DrivingSide == "left", false)
+	return e.EndObject()
+}
+
+func (e fbEncoder) intersection(in Intersection) flatbuffers.UOffsetT {
+	e.StartVector(flatbuffers.SizeInt16, len(in.Bearings), flatbuffers.SizeInt16)
+	for i := len(in.Bearings) - 1; i >= 0; i-- {
+		e.PrependInt16(int16(in.Bearings[i]))
+	}
+	bearings := e.EndVector(len(in.Bearings))
+	e.StartVector(flatbuffers.SizeBool, len(in.Entry), flatbuffers.SizeBool)
+	for i := len(in.Entry) - 1; i >= 0; i-- {
+		e.PrependBool(in.Entry[i])
+	}
+	entry := e.EndVector(len(in.Entry))
+	var classes []flatbuffers.UOffsetT
+	for _, c := range in.Classes {
+		classes = append(classes, e.CreateString(c))
+	}
+	cs := e.offsets(classes)
+	var lanes []flatbuffers.UOffsetT
+	for _, l := range in.Lanes {
+		e.StartVector(flatbuffers.SizeInt8, len(l.Indications), flatbuffers.SizeInt8)
+		for i := len(l.Indications) - 1; i >= 0; i-- {
+			e.PrependInt8(fbEnumValue(fbTurns, l.Indications[i].String()))
+		}
+		indications := e.EndVector(len(l.Indications))
+		e.StartObject(2)
+		e.PrependUOffsetTSlot(fbLaneIndications, indications, 0)
+		e.PrependBoolSlot(fbLaneValid, l.Valid, false)
+		lanes = append(lanes, e.EndObject())
+	}
+	ls := e.offsets(lanes)
+
+	e.StartObject(7)
+	e.position(fbIntersectionLocation, in.Location)
+	e.PrependUOffsetTSlot(fbIntersectionBearings, bearings, 0)
+	e.PrependUOffsetTSlot(fbIntersectionEntry, entry, 0)
+	e.PrependUOffsetTSlot(fbIntersectionClasses, cs, 0)
+	e.PrependUOffsetTSlot(fbIntersectionLanes, ls, 0)
+	// in and out are written explicitly even if zero to distinguish them from absent values
+	if in.In != nil {
+		e.PrependUint32(*in.In)
+		e.Slot(fbIntersectionInBearing)
+	}
+	if in.Out != nil {
+		e.PrependUint32(*in.Out)
+		e.Slot(fbIntersectionOutBearing)
+	}
+	return e.EndObject()
+}
+
+func fbEnumValue(names []string, name string) int8 {
+	for i, n := range names {
+		if n == name {
+			return int8(i)
+		}
+	}
+	return 0
+}
+
+func encodeRouteResponse(r RouteResponse) []byte {
+	e := newFBEncoder()
+	var waypoints, routes []flatbuffers.UOffsetT
+	for _, w := range r.Waypoints {
+		waypoints = append(waypoints, e.waypoint(w.Hint, w.Name, w.Location, w.Distance, nil))
+	}
+	for _, route := range r.Routes {
+		routes = append(routes, e.route(route, 0))
+	}
+	return e.finish(r.ResponseStatus, waypoints, routes, 0)
+}
+
+func encodeMatchResponse(r MatchResponse) []byte {
+	e := newFBEncoder()
+	var waypoints, routes []flatbuffers.UOffsetT
+	for _, tp := range r.Tracepoints {
+		if tp == nil {
+			e.StartObject(9)
+			waypoints = append(waypoints, e.EndObject())
+			continue
+		}
+		tp := tp
+		waypoints = append(waypoints, e.waypoint(tp.Hint, tp.Name, tp.Location, float32(tp.Distance), func() {
+			e.PrependUint32Slot(fbWaypointMatchingsIndex, uint32(tp.MatchingIndex), 0)
+			e.PrependUint32Slot(fbWaypointWaypointIndex, uint32(tp.Index), 0)
+			e.PrependUint32Slot(fbWaypointAlternativesCount, uint32(tp.AlternativesCount), 0)
+		}))
+	}
+	for _, m := range r.Matchings {
+		route := m.Route
+		route.Geometry = m.Geometry
+		routes = append(routes, e.route(route, m.Confidence))
+	}
+	return e.finish(r.ResponseStatus, waypoints, routes, 0)
+}
+
+func encodeNearestResponse(r NearestResponse) []byte {
+	e := newFBEncoder()
+	var waypoints []flatbuffers.UOffsetT
+	for _, w := range r.Waypoints {
+		w := w
+		waypoints = append(waypoints, e.waypoint(w.Hint, w.Name, w.Location, float32(w.Distance), func() {
+			e.Prep(flatbuffers.SizeUint64, 2*flatbuffers.SizeUint64)
+			e.PrependUint64(w.Nodes[1])
+			e.PrependUint64(w.Nodes[0])
+			e.PrependStructSlot(fbWaypointNodes, e.Offset(), 0)
+		}))
+	}
+	return e.finish(r.ResponseStatus, waypoints, nil, 0)
+}
+
+func encodeTableResponse(r TableResponse) []byte {
+	e := newFBEncoder()
+	var durations []float32
+	for _, row := range r.Durations {
+		durations = append(durations, row...)
+	}
+	var distances []float32
+	for _, row := range r.Distances {
+		distances = append(distances, row...)
+	}
+	ds := e.float32s(durations)
+	var dists flatbuffers.UOffsetT
+	if len(distances) > 0 {
+		dists = e.float32s(distances)
+	}
+	e.StartObject(6)
+	e.PrependUOffsetTSlot(fbTableDurations, ds, 0)
+	if dists != 0 {
+		e.PrependUOffsetTSlot(fbTableDistances, dists, 0)
+	}
+	e.PrependUint16Slot(fbTableRows, uint16(len(r.Durations)), 0)
+	if len(r.Durations) > 0 {
+		e.PrependUint16Slot(fbTableCols, uint16(len(r.Durations[0])), 0)
+	}
+	return e.finish(r.ResponseStatus, nil, nil, e.EndObject())
+}
+
+func fixturedResponse(t testing.TB, name string, out interface{}) {
+	require.NoError(t, json.Unmarshal(fixturedJSON(name), out))
+}
+
+func TestUnmarshalFlatbuffersRouteResponse(t *testing.T) {
+	var expected RouteResponse
+	fixturedResponse(t, "route_response_full", &expected)
+
+	var actual RouteResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeRouteResponse(expected), &actual))
+
+	require.Equal(t, expected.ResponseStatus, actual.ResponseStatus)
+	require.Len(t, actual.Waypoints, len(expected.Waypoints))
+	for i, w := range expected.Waypoints {
+		assert.Equal(t, w.Hint, actual.Waypoints[i].Hint)
+		assert.Equal(t, w.Name, actual.Waypoints[i].Name)
+		assert.InDelta(t, w.Location.Lng(), actual.Waypoints[i].Location.Lng(), 1e-5)
+		assert.InDelta(t, w.Location.Lat(), actual.Waypoints[i].Location.Lat(), 1e-5)
+	}
+
+	require.Len(t, actual.Routes, 1)
+	route := actual.Routes[0]
+	assert.Equal(t, float32(1190.5), route.Distance)
+	assert.Equal(t, float32(92.2), route.Duration)
+	require.Len(t, route.Legs, 2)
+
+	leg0 := route.Legs[0]
+	assert.Equal(t, float32(637.5), leg0.Distance)
+	assert.Len(t, leg0.Annotation.Duration, 14)
+	assert.Len(t, leg0.Annotation.Distance, 14)
+	require.Len(t, leg0.Steps, 7)
+	for i, step := range expected.Routes[0].Legs[0].Steps {
+		actual := leg0.Steps[i]
+		assert.Equal(t, step.Distance, actual.Distance)
+		assert.Equal(t, step.Duration, actual.Duration)
+		assert.Equal(t, step.Mode, actual.Mode)
+		assert.Equal(t, step.Maneuver.Type, actual.Maneuver.Type)
+		assert.Equal(t, step.Maneuver.Modifier, actual.Maneuver.Modifier)
+		assert.Equal(t, step.Geometry.Polyline(polyline6Factor), actual.Geometry.Polyline(polyline6Factor))
+		require.Len(t, actual.Intersections, len(step.Intersections))
+		for j, in := range step.Intersections {
+			assert.Equal(t, in.Bearings, actual.Intersections[j].Bearings)
+			assert.Equal(t, in.Entry, actual.Intersections[j].Entry)
+			assert.Equal(t, in.In, actual.Intersections[j].In)
+			assert.Equal(t, in.Out, actual.Intersections[j].Out)
+		}
+	}
+}
+
+func TestUnmarshalFlatbuffersRouteSteps(t *testing.T) {
+	var expected RouteResponse
+	fixturedResponse(t, "route_response_steps", &expected)
+
+	var actual RouteResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeRouteResponse(expected), &actual))
+
+	require.Len(t, actual.Routes, 1)
+	require.Len(t, actual.Routes[0].Legs, 1)
+	expectedSteps, actualSteps := expected.Routes[0].Legs[0].Steps, actual.Routes[0].Legs[0].Steps
+	require.Len(t, actualSteps, len(expectedSteps))
+	for i, step := range expectedSteps {
+		// geometries are compared in TestUnmarshalFlatbuffersRouteResponse,
+		// turn penalties are not a part of flatbuffers schema
+		step.Geometry, actualSteps[i].Geometry = Geometry{}, Geometry{}
+		for j := range step.Intersections {
+			step.Intersections[j].TurnWeight, step.Intersections[j].TurnDuration = 0, 0
+			step.Intersections[j].Location = actualSteps[i].Intersections[j].Location
+		}
+		step.Maneuver.Location = actualSteps[i].Maneuver.Location
+		assert.Equal(t, step, actualSteps[i])
+	}
+}
+
+func TestUnmarshalFlatbuffersUnknownEnum(t *testing.T) {
+	e := newFBEncoder()
+	e.StartObject(6)
+	e.PrependInt8Slot(fbManeuverType, int8(len(fbManeuverTypes)), 0)
+	e.PrependInt8Slot(fbManeuverModifier, -1, 0)
+	e.Finish(e.EndObject())
+
+	b := e.FinishedBytes()
+	m := fbManeuver(fbTable{flatbuffers.Table{Bytes: b, Pos: flatbuffers.GetUOffsetT(b)}})
+	assert.Equal(t, ManeuverType(""), m.Type)
+	assert.Equal(t, ManeuverModifier(""), m.Modifier)
+	assert.Equal(t, "turn", fbEnum(fbManeuverTypes, fbEnumValue(fbManeuverTypes, "turn")))
+}
+
+func TestUnmarshalFlatbuffersAnnotation(t *testing.T) {
+	annotation := Annotation{
+		Duration:    []float32{2, 10},
+		Distance:    []float32{20, 50},
+		Speed:       []float32{10, 5},
+		Weight:      []float32{3, 10},
+		Datasources: []uint32{0, 1},
+		Nodes:       []uint64{1, 2, 3},
+		Metadata:    &AnnotationMetadata{DatasourceNames: []string{"lua profile", "traffic"}},
+	}
+	b := encodeRouteResponse(RouteResponse{
+		ResponseStatus: ResponseStatus{Code: errorCodeOK},
+		Routes:         []Route{{Legs: []RouteLeg{{Annotation: annotation}}}},
+	})
+
+	var actual RouteResponse
+	require.NoError(t, unmarshalFlatbuffers(b, &actual))
+	assert.Equal(t, annotation, actual.Routes[0].Legs[0].Annotation)
+}
+
+func TestUnmarshalFlatbuffersMatchResponse(t *testing.T) {
+	var expected MatchResponse
+	fixturedResponse(t, "match_response_full", &expected)
+
+	var actual MatchResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeMatchResponse(expected), &actual))
+
+	assert.Equal(t, "new", actual.DataVersion)
+	require.Len(t, actual.Matchings, 1)
+	assert.InDelta(t, 0.023898, actual.Matchings[0].Confidence, 1e-6)
+	assert.Equal(t, float32(1035.3), actual.Matchings[0].Distance)
+	require.Len(t, actual.Matchings[0].Legs, 2)
+	assert.Len(t, actual.Matchings[0].Legs[0].Annotation.Nodes, 11)
+	assert.Len(t, actual.Matchings[0].Legs[1].Annotation.Nodes, 15)
+
+	require.Len(t, actual.Tracepoints, len(expected.Tracepoints))
+	for i, tp := range expected.Tracepoints {
+		if tp == nil {
+			assert.Nil(t, actual.Tracepoints[i])
+			continue
+		}
+		assert.Equal(t, tp.Index, actual.Tracepoints[i].Index)
+		assert.Equal(t, tp.MatchingIndex, actual.Tracepoints[i].MatchingIndex)
+		assert.Equal(t, tp.AlternativesCount, actual.Tracepoints[i].AlternativesCount)
+		assert.Equal(t, tp.Hint, actual.Tracepoints[i].Hint)
+		assert.Equal(t, tp.Name, actual.Tracepoints[i].Name)
+		assert.InDelta(t, tp.Distance, actual.Tracepoints[i].Distance, 1e-3)
+	}
+}
+
+func TestUnmarshalFlatbuffersNearestResponse(t *testing.T) {
+	var expected NearestResponse
+	fixturedResponse(t, "nearest_response_full", &expected)
+
+	var actual NearestResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeNearestResponse(expected), &actual))
+
+	require.Len(t, actual.Waypoints, 5)
+	for i, w := range expected.Waypoints {
+		assert.Equal(t, w.Hint, actual.Waypoints[i].Hint)
+		assert.Equal(t, w.Nodes, actual.Waypoints[i].Nodes)
+		assert.InDelta(t, w.Distance, actual.Waypoints[i].Distance, 1e-3)
+	}
+}
+
+func TestUnmarshalFlatbuffersTableResponse(t *testing.T) {
+	var expected TableResponse
+	fixturedResponse(t, "table_response_full", &expected)
+
+	var actual TableResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeTableResponse(expected), &actual))
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnmarshalFlatbuffersTableResponseWithDistances(t *testing.T) {
+	expected := TableResponse{
+		ResponseStatus: ResponseStatus{Code: errorCodeOK},
+		Durations:      [][]float32{{0, 10.5}, {11, 0}},
+		Distances:      [][]float32{{0, 120}, {130.5, 0}},
+	}
+
+	var actual TableResponse
+	require.NoError(t, unmarshalFlatbuffers(encodeTableResponse(expected), &actual))
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnmarshalFlatbuffersError(t *testing.T) {
+	b := encodeRouteResponse(RouteResponse{
+		ResponseStatus: ResponseStatus{Code: ErrorCodeNoRoute, Message: "Impossible route between points"},
+	})
+
+	var r RouteResponse
+	require.NoError(t, unmarshalFlatbuffers(b, &r))
+	assert.EqualError(t, r.apiError(), "NoRoute - Impossible route between points")
+}
+
+func TestUnmarshalFlatbuffersMalformed(t *testing.T) {
+	var r RouteResponse
+	assert.Error(t, unmarshalFlatbuffers([]byte{0xff, 0xff, 0xff, 0x7f, 0x01}, &r))
+	assert.Error(t, unmarshalFlatbuffers([]byte{0x01}, &r))
+	assert.EqualError(t, unmarshalFlatbuffers([]byte{}, nil), "flatbuffers format is not supported for <nil>")
+}
+
+func TestTableRequestWithFlatbuffersFormat(t *testing.T) {
+	var expected TableResponse
+	fixturedResponse(t, "table_response_full", &expected)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/table/v1/car/polyline({aowFrerbM}PbI~Jyd@).flatbuffers", r.URL.Path)
+		_, _ = w.Write(encodeTableResponse(expected))
+	}))
+	defer ts.Close()
+
+	r, err := NewFromURL(ts.URL).Table(context.Background(), TableRequest{
+		Profile:     "car",
+		Coordinates: geometry,
+		Format:      FormatFlatbuffers,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, expected.Durations, r.Durations)
+}
+
+func benchmarkTable(size int) TableResponse {
+	r := TableResponse{ResponseStatus: ResponseStatus{Code: errorCodeOK}}
+	r.Durations = make([][]float32, size)
+	for i := range r.Durations {
+		r.Durations[i] = make([]float32, size)
+		for j := range r.Durations[i] {
+			r.Durations[i][j] = float32(i*size+j) / 10
+		}
+	}
+	return r
+}
+
+func BenchmarkTableResponseJSON(b *testing.B) {
+	data, err := json.Marshal(benchmarkTable(500))
+	require.NoError(b, err)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var r TableResponse
+		if err := json.Unmarshal(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTableResponseFlatbuffers(b *testing.B) {
+	data := encodeTableResponse(benchmarkTable(500))
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var r TableResponse
+		if err := unmarshalFlatbuffers(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRouteResponseJSON(b *testing.B) {
+	data := fixturedJSON("route_response_full")
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var r RouteResponse
+		if err := json.Unmarshal(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRouteResponseFlatbuffers(b *testing.B) {
+	var resp RouteResponse
+	fixturedResponse(b, "route_response_full", &resp)
+	data := encodeRouteResponse(resp)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var r RouteResponse
+		if err := unmarshalFlatbuffers(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
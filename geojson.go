@@ -0,0 +1,148 @@
+package osrm
+
+import (
+	geo "github.com/paulmach/go.geo"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// Values of "type" property of exported GeoJSON features
+const (
+	geojsonFeatureRoute      = "route"
+	geojsonFeatureMatching   = "matching"
+	geojsonFeatureStep       = "step"
+	geojsonFeatureWaypoint   = "waypoint"
+	geojsonFeatureTracepoint = "tracepoint"
+)
+
+// ToGeoJSON exports routes, their steps and waypoints as a GeoJSON feature collection.
+// Every feature has a "type" property which is one of "route", "step" or "waypoint".
+func (r RouteResponse) ToGeoJSON() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for i, route := range r.Routes {
+		if f := lineStringFeature(route.Geometry); f != nil {
+			f.SetProperty("type", geojsonFeatureRoute)
+			f.SetProperty("route_index", i)
+			setRouteProperties(f, route)
+			fc.AddFeature(f)
+		}
+		addStepFeatures(fc, "route_index", i, route)
+	}
+	for i, w := range r.Waypoints {
+		f := pointFeature(w.Location)
+		f.SetProperty("type", geojsonFeatureWaypoint)
+		f.SetProperty("waypoint_index", i)
+		f.SetProperty("name", w.Name)
+		f.SetProperty("distance", w.Distance)
+		fc.AddFeature(f)
+	}
+	return fc
+}
+
+// ToGeoJSON exports matchings, their steps and tracepoints as a GeoJSON feature collection.
+// Every feature has a "type" property which is one of "matching", "step" or "tracepoint".
+// Unmatched tracepoints are omitted.
+func (r MatchResponse) ToGeoJSON() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for i, m := range r.Matchings {
+		if f := lineStringFeature(m.Geometry); f != nil {
+			f.SetProperty("type", geojsonFeatureMatching)
+			f.SetProperty("matchings_index", i)
+			f.SetProperty("confidence", m.Confidence)
+			setRouteProperties(f, m.Route)
+			fc.AddFeature(f)
+		}
+		addStepFeatures(fc, "matchings_index", i, m.Route)
+	}
+	for i, tp := range r.Tracepoints {
+		if tp == nil {
+			continue
+		}
+		f := pointFeature(tp.Location)
+		f.SetProperty("type", geojsonFeatureTracepoint)
+		f.SetProperty("trace_index", i)
+		f.SetProperty("waypoint_index", tp.Index)
+		f.SetProperty("matchings_index", tp.MatchingIndex)
+		f.SetProperty("name", tp.Name)
+		f.SetProperty("distance", tp.Distance)
+		if tp.MatchingIndex < len(r.Matchings) {
+			f.SetProperty("confidence", r.Matchings[tp.MatchingIndex].Confidence)
+		}
+		fc.AddFeature(f)
+	}
+	return fc
+}
+
+// ToGeoJSON exports nearest waypoints as a GeoJSON feature collection of "waypoint" type points.
+func (r NearestResponse) ToGeoJSON() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for i, w := range r.Waypoints {
+		f := pointFeature(w.Location)
+		f.SetProperty("type", geojsonFeatureWaypoint)
+		f.SetProperty("waypoint_index", i)
+		f.SetProperty("name", w.Name)
+		f.SetProperty("distance", w.Distance)
+		f.SetProperty("nodes", w.Nodes)
+		fc.AddFeature(f)
+	}
+	return fc
+}
+
+func setRouteProperties(f *geojson.Feature, r Route) {
+	f.SetProperty("distance", r.Distance)
+	f.SetProperty("duration", r.Duration)
+	f.SetProperty("weight", r.Wieght)
+	f.SetProperty("weight_name", r.WeightName)
+}
+
+func addStepFeatures(fc *geojson.FeatureCollection, indexName string, index int, r Route) {
+	for legIndex, leg := range r.Legs {
+		for stepIndex, step := range leg.Steps {
+			f := lineStringFeature(step.Geometry)
+			if f == nil {
+				f = pointFeature(step.Maneuver.Location)
+			}
+			f.SetProperty("type", geojsonFeatureStep)
+			f.SetProperty(indexName, index)
+			f.SetProperty("leg_index", legIndex)
+			f.SetProperty("step_index", stepIndex)
+			f.SetProperty("name", step.Name)
+			f.SetProperty("distance", step.Distance)
+			f.SetProperty("duration", step.Duration)
+			f.SetProperty("mode", step.Mode)
+			f.SetProperty("maneuver", maneuverProperties(step.Maneuver))
+			fc.AddFeature(f)
+		}
+	}
+}
+
+func maneuverProperties(m StepManeuver) map[string]interface{} {
+	props := map[string]interface{}{
+		"type":           m.Type,
+		"location":       []float64{m.Location.Lng(), m.Location.Lat()},
+		"bearing_before": m.BearingBefore,
+		"bearing_after":  m.BearingAfter,
+	}
+	if m.Modifier != "" {
+		props["modifier"] = m.Modifier
+	}
+	if m.Exit != nil {
+		props["exit"] = *m.Exit
+	}
+	return props
+}
+
+// lineStringFeature returns nil for geometries not suitable for a line string, e.g. if overview is not requested
+func lineStringFeature(g Geometry) *geojson.Feature {
+	if len(g.PointSet) < 2 {
+		return nil
+	}
+	coordinates := make([][]float64, len(g.PointSet))
+	for i, p := range g.PointSet {
+		coordinates[i] = []float64{p.Lng(), p.Lat()}
+	}
+	return geojson.NewLineStringFeature(coordinates)
+}
+
+func pointFeature(p geo.Point) *geojson.Feature {
+	return geojson.NewPointFeature([]float64{p.Lng(), p.Lat()})
+}
@@ -0,0 +1,92 @@
+package osrm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func featureTypes(features []map[string]interface{}) map[string]int {
+	types := map[string]int{}
+	for _, f := range features {
+		types[f["properties"].(map[string]interface{})["type"].(string)]++
+	}
+	return types
+}
+
+func marshalFeatures(t *testing.T, v interface{}) []map[string]interface{} {
+	bytes, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var fc struct {
+		Type     string                   `json:"type"`
+		Features []map[string]interface{} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(bytes, &fc))
+	require.Equal(t, "FeatureCollection", fc.Type)
+	return fc.Features
+}
+
+func TestRouteResponseToGeoJSON(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+
+	features := marshalFeatures(t, resp.ToGeoJSON())
+
+	assert.Equal(t, map[string]int{"route": 1, "step": 4, "waypoint": 2}, featureTypes(features))
+
+	route := features[0]
+	assert.Equal(t, "LineString", route["geometry"].(map[string]interface{})["type"])
+	assert.Equal(t, 1886.3, route["properties"].(map[string]interface{})["distance"])
+
+	rotary := features[3]["properties"].(map[string]interface{})
+	assert.Equal(t, "Torstraße", rotary["name"])
+	assert.Equal(t, map[string]interface{}{
+		"type":           "rotary",
+		"modifier":       "right",
+		"exit":           2.0,
+		"location":       []interface{}{13.391447, 52.524933},
+		"bearing_before": 3.0,
+		"bearing_after":  60.0,
+	}, rotary["maneuver"])
+
+	// the arrival step has a single point geometry
+	arrive := features[4]
+	assert.Equal(t, "Point", arrive["geometry"].(map[string]interface{})["type"])
+
+	waypoint := features[5]
+	assert.Equal(t, map[string]interface{}{
+		"type":        "Point",
+		"coordinates": []interface{}{13.388799, 52.517033},
+	}, waypoint["geometry"])
+	assert.Equal(t, "Friedrichstraße", waypoint["properties"].(map[string]interface{})["name"])
+}
+
+func TestMatchResponseToGeoJSON(t *testing.T) {
+	var resp MatchResponse
+	fixturedResponse(t, "match_response_full", &resp)
+
+	features := marshalFeatures(t, resp.ToGeoJSON())
+
+	// the fixture has no overview geometry, so the matching itself is omitted
+	types := featureTypes(features)
+	assert.Equal(t, 0, types["matching"])
+	assert.NotZero(t, types["step"])
+	assert.Equal(t, 3, types["tracepoint"])
+
+	tracepoint := features[len(features)-1]["properties"].(map[string]interface{})
+	assert.Equal(t, 0.023898, tracepoint["confidence"])
+	assert.Equal(t, 2.0, tracepoint["trace_index"])
+}
+
+func TestNearestResponseToGeoJSON(t *testing.T) {
+	var resp NearestResponse
+	fixturedResponse(t, "nearest_response_full", &resp)
+
+	features := marshalFeatures(t, resp.ToGeoJSON())
+
+	assert.Equal(t, map[string]int{"waypoint": 5}, featureTypes(features))
+	assert.Len(t, features[0]["properties"].(map[string]interface{})["nodes"], 2)
+}
@@ -0,0 +1,172 @@
+package osrm
+
+import (
+	"fmt"
+	"math"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// geometryLocationTolerance is the maximal difference in degrees between the first point of a geometry
+// and the location it should start at. It covers rounding to polyline5 precision and is far below
+// the tenfold difference caused by decoding a geometry with a wrong precision, except near zero coordinates.
+// It is used only by CheckGeometries as the check is a heuristic.
+const geometryLocationTolerance = 1e-3
+
+// DecodeGeometries interprets geometries of the routes and their steps according to the geometries value
+// they were requested with. Route calls it for every response, so it's only needed for responses unmarshaled on their own.
+// ErrGeometryPrecisionMismatch is returned if the geometries are of another format or have invalid coordinates.
+func (r *RouteResponse) DecodeGeometries(requested Geometries) error {
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		if err := decodeGeometry(&route.Geometry, requested); err != nil {
+			return err
+		}
+		if err := decodeLegGeometries(route.Legs, requested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckGeometries checks that decoded route and step geometries start at their waypoint and maneuver locations.
+// A polyline encoded with another precision is decoded into valid but scaled coordinates, which is detected this way.
+// The check is a heuristic unreliable near zero coordinates, thus Route doesn't call it.
+// ErrGeometryPrecisionMismatch is returned if a geometry starts too far from its location.
+func (r RouteResponse) CheckGeometries() error {
+	for _, route := range r.Routes {
+		if len(r.Waypoints) > 0 {
+			if err := checkGeometryStart(route.Geometry, r.Waypoints[0].Location); err != nil {
+				return err
+			}
+		}
+		if err := checkLegGeometries(route.Legs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckGeometries checks that decoded step geometries start at their maneuver locations.
+// The check is a heuristic unreliable near zero coordinates, thus Match doesn't call it.
+// ErrGeometryPrecisionMismatch is returned if a geometry starts too far from its location.
+func (r MatchResponse) CheckGeometries() error {
+	for _, m := range r.Matchings {
+		if err := checkLegGeometries(m.Legs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeGeometries interprets geometries of the matchings and their steps according to the geometries value
+// they were requested with. Match calls it for every response, so it's only needed for responses unmarshaled on their own.
+// ErrGeometryPrecisionMismatch is returned if the geometries are of another format or have invalid coordinates.
+func (r *MatchResponse) DecodeGeometries(requested Geometries) error {
+	for i := range r.Matchings {
+		m := &r.Matchings[i]
+		if err := decodeGeometry(&m.Geometry, requested); err != nil {
+			return err
+		}
+		if err := decodeLegGeometries(m.Legs, requested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetGeometries changes the format geometries of the response are marshaled to
+func (r *RouteResponse) SetGeometries(encoding Geometries) {
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		route.Geometry.Encoding = encoding
+		setLegGeometries(route.Legs, encoding)
+	}
+}
+
+// SetGeometries changes the format geometries of the response are marshaled to
+func (r *MatchResponse) SetGeometries(encoding Geometries) {
+	for i := range r.Matchings {
+		m := &r.Matchings[i]
+		m.Geometry.Encoding = encoding
+		setLegGeometries(m.Legs, encoding)
+	}
+}
+
+func decodeLegGeometries(legs []RouteLeg, requested Geometries) error {
+	for i := range legs {
+		for j := range legs[i].Steps {
+			step := &legs[i].Steps[j]
+			if err := decodeGeometry(&step.Geometry, requested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkLegGeometries(legs []RouteLeg) error {
+	for _, leg := range legs {
+		for _, step := range leg.Steps {
+			if err := checkGeometryStart(step.Geometry, step.Maneuver.Location); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setLegGeometries(legs []RouteLeg, encoding Geometries) {
+	for i := range legs {
+		for j := range legs[i].Steps {
+			legs[i].Steps[j].Geometry.Encoding = encoding
+		}
+	}
+}
+
+// decodeGeometry rescales a geometry unmarshaled as polyline6 if polyline5 was requested
+// and checks its coordinates are valid. Polylines are unmarshaled without encoding
+// as their precision is unknown until the requested geometries value is known.
+// Polyline6 geometries are left without encoding as it's the default one.
+func decodeGeometry(g *Geometry, requested Geometries) error {
+	if requested == GeometriesPolyline6 {
+		requested = ""
+	}
+	if g.Encoding == GeometriesPolyline6 {
+		g.Encoding = ""
+	}
+	if len(g.PointSet) == 0 || (requested != "" && g.Encoding == requested) {
+		g.Encoding = requested
+		return nil
+	}
+	if g.Encoding != "" || requested == GeometriesGeojson {
+		return fmt.Errorf("%w: %s geometry received instead of %s", ErrGeometryPrecisionMismatch,
+			valueOrDefault(g.Encoding, GeometriesPolyline), valueOrDefault(requested, GeometriesPolyline6))
+	}
+
+	for i, p := range g.PointSet {
+		if requested == GeometriesPolyline {
+			p = geo.Point{
+				math.Round(p.Lng()*polyline6Factor) / polyline5Factor,
+				math.Round(p.Lat()*polyline6Factor) / polyline5Factor,
+			}
+			g.PointSet[i] = p
+		}
+		if math.Abs(p.Lng()) > 180 || math.Abs(p.Lat()) > 90 {
+			return fmt.Errorf("%w: %s geometry has invalid coordinates %v", ErrGeometryPrecisionMismatch, valueOrDefault(requested, GeometriesPolyline6), p)
+		}
+	}
+	g.Encoding = requested
+	return nil
+}
+
+func checkGeometryStart(g Geometry, location geo.Point) error {
+	if len(g.PointSet) == 0 {
+		return nil
+	}
+	start := g.PointSet[0]
+	if math.Abs(start.Lng()-location.Lng()) > geometryLocationTolerance || math.Abs(start.Lat()-location.Lat()) > geometryLocationTolerance {
+		return fmt.Errorf("%w: %s geometry starts at %v instead of %v", ErrGeometryPrecisionMismatch, valueOrDefault(g.Encoding, GeometriesPolyline6), start, location)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func routeResponseHandler(t *testing.T, resp RouteResponse, encoding Geometries) http.HandlerFunc {
+	resp.SetGeometries(encoding)
+	body, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	}
+}
+
+func TestRouteRequestWithPolyline5Geometries(t *testing.T) {
+	var expected RouteResponse
+	fixturedResponse(t, "route_response_steps", &expected)
+
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		routeResponseHandler(t, expected, GeometriesPolyline)(w, r)
+	}))
+	defer ts.Close()
+
+	resp, err := NewFromURL(ts.URL).Route(context.Background(), RouteRequest{
+		Profile:     "car",
+		Coordinates: geometry,
+		Steps:       StepsTrue,
+		Geometries:  GeometriesPolyline,
+		Overview:    OverviewFull,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "geometries=polyline&overview=full&steps=true", query)
+
+	actual := resp.Routes[0].Geometry
+	assert.Equal(t, GeometriesPolyline, actual.Encoding)
+	require.Len(t, actual.PointSet, len(expected.Routes[0].Geometry.PointSet))
+	for i, p := range expected.Routes[0].Geometry.PointSet {
+		assert.InDelta(t, p.Lng(), actual.PointSet[i].Lng(), 1e-5)
+		assert.InDelta(t, p.Lat(), actual.PointSet[i].Lat(), 1e-5)
+	}
+	assert.Equal(t, float64(13.3888), actual.PointSet[0].Lng())
+
+	step := resp.Routes[0].Legs[0].Steps[1].Geometry
+	assert.Equal(t, GeometriesPolyline, step.Encoding)
+	assert.InDelta(t, 13.389107, step.PointSet[0].Lng(), 1e-5)
+
+	bytes, err := json.Marshal(step)
+	require.NoError(t, err)
+	assert.Equal(t, `"`+step.Polyline(polyline5Factor)+`"`, string(bytes))
+}
+
+func TestRouteRequestGeometriesMismatch(t *testing.T) {
+	var fixture RouteResponse
+	fixturedResponse(t, "route_response_steps", &fixture)
+
+	cases := []struct {
+		name      string
+		encoding  Geometries
+		requested Geometries
+		// scaled geometries are valid and detected only by CheckGeometries
+		scaled bool
+	}{
+		{"polyline6 for polyline", GeometriesPolyline6, GeometriesPolyline, false},
+		{"polyline for polyline6", GeometriesPolyline, GeometriesPolyline6, true},
+		{"polyline for default", GeometriesPolyline, "", true},
+		{"polyline6 for geojson", GeometriesPolyline6, GeometriesGeojson, false},
+		{"geojson for polyline", GeometriesGeojson, GeometriesPolyline, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ts := httptest.NewServer(routeResponseHandler(t, fixture, c.encoding))
+			defer ts.Close()
+
+			resp, err := NewFromURL(ts.URL).Route(context.Background(), RouteRequest{
+				Profile:     "car",
+				Coordinates: geometry,
+				Steps:       StepsTrue,
+				Geometries:  c.requested,
+			})
+			if c.scaled {
+				require.NoError(t, err)
+				err = resp.CheckGeometries()
+			}
+			assert.True(t, errors.Is(err, ErrGeometryPrecisionMismatch), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestRouteResponseCheckGeometries(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+	require.NoError(t, resp.DecodeGeometries(GeometriesPolyline6))
+	assert.NoError(t, resp.CheckGeometries())
+
+	resp.Routes[0].Legs[0].Steps[1].Maneuver.Location = geo.Point{1.3, 5.2}
+	assert.True(t, errors.Is(resp.CheckGeometries(), ErrGeometryPrecisionMismatch))
+}
+
+func TestRouteRequestWithGeojsonGeometries(t *testing.T) {
+	var fixture RouteResponse
+	fixturedResponse(t, "route_response_steps", &fixture)
+
+	ts := httptest.NewServer(routeResponseHandler(t, fixture, GeometriesGeojson))
+	defer ts.Close()
+
+	resp, err := NewFromURL(ts.URL).Route(context.Background(), RouteRequest{
+		Profile:     "car",
+		Coordinates: geometry,
+		Geometries:  GeometriesGeojson,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fixture.Routes[0].Geometry.PointSet, resp.Routes[0].Geometry.PointSet)
+	assert.Equal(t, GeometriesGeojson, resp.Routes[0].Geometry.Encoding)
+}
+
+func TestMatchResponseDecodeGeometries(t *testing.T) {
+	var resp MatchResponse
+	fixturedResponse(t, "match_response_full", &resp)
+
+	require.NoError(t, resp.DecodeGeometries(GeometriesPolyline))
+	assert.Equal(t, GeometriesPolyline, resp.Matchings[0].Legs[0].Steps[0].Geometry.Encoding)
+
+	// decoding again with another precision is a mismatch
+	err := resp.DecodeGeometries(GeometriesPolyline6)
+	assert.True(t, errors.Is(err, ErrGeometryPrecisionMismatch))
+}
@@ -1,7 +1,10 @@
 module github.com/openmarketplaceengine/go-osrm
 
+go 1.13
+
 require (
+	github.com/google/flatbuffers v1.12.1
 	github.com/paulmach/go.geo v0.0.0-20180829195134-22b514266d33
-	github.com/paulmach/go.geojson v1.4.0 // indirect
+	github.com/paulmach/go.geojson v1.4.0
 	github.com/stretchr/testify v1.3.0
 )
@@ -0,0 +1,198 @@
+package osrm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+const (
+	gpxVersion   = "1.1"
+	gpxNamespace = "http://www.topografix.com/GPX/1/1"
+	gpxCreator   = "go-osrm"
+)
+
+type gpx struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Xmlns     string     `xml:"xmlns,attr,omitempty"`
+	Version   string     `xml:"version,attr"`
+	Creator   string     `xml:"creator,attr"`
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []gpxRoute `xml:"rte"`
+	Tracks    []gpxTrack `xml:"trk"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxPoint struct {
+	Lat        float64        `xml:"lat,attr"`
+	Lon        float64        `xml:"lon,attr"`
+	Time       *time.Time     `xml:"time,omitempty"`
+	Name       string         `xml:"name,omitempty"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+}
+
+// gpxExtensions holds the horizontal accuracy in meters reported by many tracking apps
+type gpxExtensions struct {
+	Accuracy *float64 `xml:"accuracy,omitempty"`
+}
+
+func newGPXPoint(p geo.Point) gpxPoint {
+	return gpxPoint{Lat: p.Lat(), Lon: p.Lng()}
+}
+
+func (p gpxPoint) location() geo.Point {
+	return geo.Point{p.Lon, p.Lat}
+}
+
+func (p gpxPoint) accuracy() float64 {
+	if p.Extensions == nil || p.Extensions.Accuracy == nil {
+		return 0
+	}
+	return *p.Extensions.Accuracy
+}
+
+// ParseGPX parses a GPX document into a match request.
+// Points of all track segments are used in order, route points are used if there are no tracks.
+// Timestamps are taken from <time> elements if all the points have them.
+// Radiuses are taken from <accuracy> extension elements, OSRM default of 5 meters
+// is used for points without accuracy if any other point has it.
+// Profile and other options of the request should be set by the caller.
+func ParseGPX(r io.Reader) (MatchRequest, error) {
+	var doc gpx
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return MatchRequest{}, fmt.Errorf("osrm5: failed to parse GPX: %v", err)
+	}
+
+	var points []gpxPoint
+	for _, t := range doc.Tracks {
+		for _, s := range t.Segments {
+			points = append(points, s.Points...)
+		}
+	}
+	if len(points) == 0 {
+		for _, rte := range doc.Routes {
+			points = append(points, rte.Points...)
+		}
+	}
+	if len(points) == 0 {
+		return MatchRequest{}, ErrGPXNoPoints
+	}
+
+	withTimestamps, withRadiuses := true, false
+	for _, p := range points {
+		withTimestamps = withTimestamps && p.Time != nil
+		withRadiuses = withRadiuses || p.accuracy() > 0
+	}
+
+	req := MatchRequest{
+		Coordinates: NewGeometryFromPointSet(make(geo.PointSet, len(points))),
+	}
+	for i, p := range points {
+		req.Coordinates.PointSet[i] = p.location()
+		if withTimestamps {
+			req.Timestamps = append(req.Timestamps, p.Time.Unix())
+		}
+		if withRadiuses {
+			radius := p.accuracy()
+			if radius <= 0 {
+				radius = defaultMatchSessionRadius
+			}
+			req.Radiuses = append(req.Radiuses, radius)
+		}
+	}
+	return req, nil
+}
+
+// WriteGPX writes the trace of the request as a GPX track with timestamps and accuracies if set
+func (r MatchRequest) WriteGPX(w io.Writer) error {
+	segment := gpxSegment{Points: make([]gpxPoint, len(r.Coordinates.PointSet))}
+	for i, p := range r.Coordinates.PointSet {
+		point := newGPXPoint(p)
+		if i < len(r.Timestamps) {
+			t := time.Unix(r.Timestamps[i], 0).UTC()
+			point.Time = &t
+		}
+		if i < len(r.Radiuses) {
+			radius := r.Radiuses[i]
+			point.Extensions = &gpxExtensions{Accuracy: &radius}
+		}
+		segment.Points[i] = point
+	}
+	return writeGPX(w, gpx{
+		Tracks: []gpxTrack{{Segments: []gpxSegment{segment}}},
+	})
+}
+
+// WriteGPX writes geometries of the routes as GPX tracks and the waypoints as GPX waypoints.
+// Routes should be requested with overview geometry, otherwise their tracks are empty.
+func (r RouteResponse) WriteGPX(w io.Writer) error {
+	var doc gpx
+	for _, wp := range r.Waypoints {
+		p := newGPXPoint(wp.Location)
+		p.Name = wp.Name
+		doc.Waypoints = append(doc.Waypoints, p)
+	}
+	for _, route := range r.Routes {
+		doc.Tracks = append(doc.Tracks, newGPXTrack(route.Geometry))
+	}
+	return writeGPX(w, doc)
+}
+
+// WriteGPX writes geometries of the matchings as GPX tracks and the matched tracepoints as GPX waypoints.
+// Matchings should be requested with overview geometry, otherwise their tracks are empty.
+func (r MatchResponse) WriteGPX(w io.Writer) error {
+	var doc gpx
+	for _, tp := range r.Tracepoints {
+		if tp == nil {
+			continue
+		}
+		p := newGPXPoint(tp.Location)
+		p.Name = tp.Name
+		doc.Waypoints = append(doc.Waypoints, p)
+	}
+	for _, m := range r.Matchings {
+		doc.Tracks = append(doc.Tracks, newGPXTrack(m.Geometry))
+	}
+	return writeGPX(w, doc)
+}
+
+func newGPXTrack(g Geometry) gpxTrack {
+	segment := gpxSegment{Points: make([]gpxPoint, len(g.PointSet))}
+	for i, p := range g.PointSet {
+		segment.Points[i] = newGPXPoint(p)
+	}
+	return gpxTrack{Segments: []gpxSegment{segment}}
+}
+
+func writeGPX(w io.Writer, doc gpx) error {
+	doc.Xmlns = gpxNamespace
+	doc.Version = gpxVersion
+	doc.Creator = gpxCreator
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("osrm5: failed to write GPX: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
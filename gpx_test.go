@@ -0,0 +1,101 @@
+package osrm
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseGPXFixture(t *testing.T, name string) MatchRequest {
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	req, err := ParseGPX(f)
+	require.NoError(t, err)
+	return req
+}
+
+func TestParseGPX(t *testing.T) {
+	req := parseGPXFixture(t, "trace.gpx")
+
+	assert.Equal(t, geo.PointSet{
+		{-73.990195, 40.714703},
+		{-73.989754, 40.715553},
+		{-73.987542, 40.715421},
+		{-73.985746, 40.715655},
+	}, req.Coordinates.PointSet)
+	assert.Equal(t, []int64{1615795200, 1615795215, 1615795231, 1615795245}, req.Timestamps)
+	assert.Equal(t, []float64{8, 5, 12.5, 4}, req.Radiuses)
+}
+
+func TestParseGPXRoutePoints(t *testing.T) {
+	req, err := ParseGPX(strings.NewReader(`<gpx version="1.0">
+		<rte>
+			<rtept lat="52.517033" lon="13.388799"><time>2021-03-15T08:00:00Z</time></rtept>
+			<rtept lat="52.529432" lon="13.39763"></rtept>
+		</rte>
+	</gpx>`))
+	require.NoError(t, err)
+
+	assert.Equal(t, geo.PointSet{{13.388799, 52.517033}, {13.39763, 52.529432}}, req.Coordinates.PointSet)
+	// timestamps are skipped as not all the points have them
+	assert.Empty(t, req.Timestamps)
+	assert.Empty(t, req.Radiuses)
+}
+
+func TestParseGPXFailure(t *testing.T) {
+	_, err := ParseGPX(strings.NewReader(`<gpx version="1.1"><trk><trkseg></trkseg></trk></gpx>`))
+	assert.Equal(t, ErrGPXNoPoints, err)
+
+	_, err = ParseGPX(strings.NewReader(`<gpx><trk>`))
+	assert.Error(t, err)
+}
+
+func TestMatchRequestGPXRoundTrip(t *testing.T) {
+	req := parseGPXFixture(t, "trace.gpx")
+
+	var buf bytes.Buffer
+	require.NoError(t, req.WriteGPX(&buf))
+
+	parsed, err := ParseGPX(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
+
+func TestRouteResponseWriteGPX(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+
+	var buf bytes.Buffer
+	require.NoError(t, resp.WriteGPX(&buf))
+	assert.Contains(t, buf.String(), `<wpt lat="52.517033" lon="13.388799">`)
+	assert.Contains(t, buf.String(), `<name>Torstraße</name>`)
+
+	parsed, err := ParseGPX(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, resp.Routes[0].Geometry.PointSet, parsed.Coordinates.PointSet)
+}
+
+func TestMatchResponseWriteGPX(t *testing.T) {
+	var resp MatchResponse
+	fixturedResponse(t, "match_response_full", &resp)
+	resp.Matchings[0].Geometry = NewGeometryFromPointSet(geo.PointSet{
+		{-73.990195, 40.714703},
+		{-73.987542, 40.715421},
+		{-73.985746, 40.715655},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, resp.WriteGPX(&buf))
+	assert.Equal(t, 3, strings.Count(buf.String(), "<wpt "))
+
+	parsed, err := ParseGPX(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, resp.Matchings[0].Geometry.PointSet, parsed.Coordinates.PointSet)
+}
@@ -0,0 +1,173 @@
+package osrm
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Times in seconds before a maneuver at which it's announced
+const (
+	guidanceFarAnnouncementTime  = 60.0
+	guidanceNearAnnouncementTime = 15.0
+	guidanceNowAnnouncementTime  = 5.0
+	guidanceMinNowDistance       = 15.0 // meters
+)
+
+// VoiceInstruction represents an announcement of a maneuver
+type VoiceInstruction struct {
+	// DistanceAlongGeometry is the distance in meters before the maneuver at which the announcement is played
+	DistanceAlongGeometry float64
+	Announcement          string
+}
+
+// BannerInstruction represents a visual instruction of a maneuver with lane guidance
+type BannerInstruction struct {
+	// DistanceAlongGeometry is the distance in meters before the maneuver at which the banner is shown
+	DistanceAlongGeometry float64
+	Text                  string
+	Type                  ManeuverType
+	Modifier              ManeuverModifier
+	// Lanes are the lanes at the maneuver, the valid ones lead to the route
+	Lanes []Lane
+}
+
+// StepGuidance represents instructions given to a driver along a step,
+// they describe the maneuver at the end of the step, i.e. the maneuver of the next step
+type StepGuidance struct {
+	Voice   []VoiceInstruction
+	Banners []BannerInstruction
+}
+
+// LegGuidance returns voice and banner instructions for every step of the leg.
+// Maneuvers are announced about a minute, 15 seconds and 5 seconds ahead according to the speed
+// derived from annotations, the step speed is used if the leg has no distance and duration annotations.
+// The first step also announces the departure, the arrival step has no instructions.
+func (i Instructor) LegGuidance(leg RouteLeg, opts InstructionOptions) []StepGuidance {
+	guidance := make([]StepGuidance, len(leg.Steps))
+	speeds := stepSpeeds(leg)
+
+	for s := 0; s+1 < len(leg.Steps); s++ {
+		step, next := leg.Steps[s], leg.Steps[s+1]
+		distance := float64(step.Distance)
+
+		if s == 0 {
+			guidance[s].Voice = append(guidance[s].Voice, VoiceInstruction{
+				DistanceAlongGeometry: distance,
+				Announcement:          i.Compile(step, opts),
+			})
+		}
+
+		upcoming := opts
+		upcoming.Upcoming = true
+		instruction := i.Compile(next, upcoming)
+
+		speed := speeds[s]
+		far := roundAnnouncementDistance(speed * guidanceFarAnnouncementTime)
+		near := roundAnnouncementDistance(speed * guidanceNearAnnouncementTime)
+		now := math.Min(distance, math.Max(speed*guidanceNowAnnouncementTime, guidanceMinNowDistance))
+		for _, d := range []float64{far, near} {
+			if d < distance && d > now && (len(guidance[s].Voice) == 0 || d < guidance[s].Voice[len(guidance[s].Voice)-1].DistanceAlongGeometry) {
+				guidance[s].Voice = append(guidance[s].Voice, VoiceInstruction{
+					DistanceAlongGeometry: d,
+					Announcement:          i.inDistance(d, instruction),
+				})
+			}
+		}
+		guidance[s].Voice = append(guidance[s].Voice, VoiceInstruction{
+			DistanceAlongGeometry: now,
+			Announcement:          i.Compile(next, opts),
+		})
+
+		banner := BannerInstruction{
+			DistanceAlongGeometry: distance,
+			Text:                  i.Compile(next, opts),
+			Type:                  next.Maneuver.Type,
+			Modifier:              next.Maneuver.Modifier,
+		}
+		if len(next.Intersections) > 0 {
+			banner.Lanes = next.Intersections[0].Lanes
+		}
+		guidance[s].Banners = append(guidance[s].Banners, banner)
+	}
+	return guidance
+}
+
+// RouteGuidance returns voice and banner instructions for every step of every leg of the route
+func (i Instructor) RouteGuidance(r Route) [][]StepGuidance {
+	guidance := make([][]StepGuidance, len(r.Legs))
+	for l, leg := range r.Legs {
+		guidance[l] = i.LegGuidance(leg, InstructionOptions{LegIndex: l, LegCount: len(r.Legs)})
+	}
+	return guidance
+}
+
+func (i Instructor) inDistance(distance float64, instruction string) string {
+	if i.catalog.CapitalizeFirstLetter {
+		instruction = decapitalize(instruction)
+	}
+	announcement := strings.NewReplacer(
+		"{distance}", i.formatDistance(distance),
+		"{instruction}", instruction,
+	).Replace(i.catalog.InDistance)
+	if i.catalog.CapitalizeFirstLetter {
+		announcement = capitalize(announcement)
+	}
+	return announcement
+}
+
+func (i Instructor) formatDistance(meters float64) string {
+	if meters < 1000 {
+		return strings.Replace(i.catalog.Meters, "{distance}", strconv.Itoa(int(meters)), 1)
+	}
+	if meters == 1000 {
+		return i.catalog.Kilometer
+	}
+	return strings.Replace(i.catalog.Kilometers, "{distance}", strconv.FormatFloat(meters/1000, 'f', -1, 64), 1)
+}
+
+// roundAnnouncementDistance rounds the distance down to a value convenient to announce
+func roundAnnouncementDistance(d float64) float64 {
+	r := 50.0
+	switch {
+	case d >= 1000:
+		r = 500
+	case d >= 100:
+		r = 100
+	}
+	return math.Floor(d/r) * r
+}
+
+// stepSpeeds returns speeds in meters per second along the steps of the leg.
+// Annotation segments are assigned to steps by their position along the leg.
+func stepSpeeds(leg RouteLeg) []float64 {
+	speeds := make([]float64, len(leg.Steps))
+
+	a := leg.Annotation
+	var segment int
+	var position, stepEnd float64
+	for s, step := range leg.Steps {
+		stepEnd += float64(step.Distance)
+
+		var distance, duration float64
+		for ; segment < len(a.Distance) && segment < len(a.Duration); segment++ {
+			d := float64(a.Distance[segment])
+			if position+d/2 > stepEnd {
+				break
+			}
+			position += d
+			distance += d
+			duration += float64(a.Duration[segment])
+		}
+
+		switch {
+		case duration > 0:
+			speeds[s] = distance / duration
+		case step.Duration > 0:
+			speeds[s] = float64(step.Distance / step.Duration)
+		case leg.Duration > 0:
+			speeds[s] = float64(leg.Distance / leg.Duration)
+		}
+	}
+	return speeds
+}
@@ -0,0 +1,111 @@
+package osrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func guidanceLeg() RouteLeg {
+	return RouteLeg{
+		Distance: 1600,
+		Duration: 120,
+		Annotation: Annotation{
+			Distance: []float32{500, 500, 500, 50, 50},
+			Duration: []float32{30, 30, 40, 10, 10},
+		},
+		Steps: []RouteStep{
+			{
+				Name:     "Main Street",
+				Distance: 1500,
+				Duration: 100,
+				Maneuver: StepManeuver{Type: ManeuverTypeDepart, BearingAfter: 5},
+			},
+			{
+				Name:     "Broadway",
+				Distance: 100,
+				Duration: 20,
+				Maneuver: StepManeuver{Type: ManeuverTypeTurn, Modifier: ManeuverModifierLeft},
+				Intersections: []Intersection{{
+					Lanes: []Lane{
+						{Indications: []LaneIndication{LaneIndicationLeft}, Valid: true},
+						{Indications: []LaneIndication{LaneIndicationStraight}, Valid: false},
+					},
+				}},
+			},
+			{
+				Name:     "Broadway",
+				Maneuver: StepManeuver{Type: ManeuverTypeArrive},
+			},
+		},
+	}
+}
+
+func TestLegGuidance(t *testing.T) {
+	leg := guidanceLeg()
+	guidance := NewInstructor(EnglishInstructionCatalog()).LegGuidance(leg, InstructionOptions{})
+	require.Len(t, guidance, 3)
+
+	assert.Equal(t, []VoiceInstruction{
+		{DistanceAlongGeometry: 1500, Announcement: "Head north on Main Street"},
+		{DistanceAlongGeometry: 900, Announcement: "In 900 meters, turn left onto Broadway"},
+		{DistanceAlongGeometry: 200, Announcement: "In 200 meters, turn left onto Broadway"},
+		{DistanceAlongGeometry: 75, Announcement: "Turn left onto Broadway"},
+	}, guidance[0].Voice)
+	assert.Equal(t, []BannerInstruction{{
+		DistanceAlongGeometry: 1500,
+		Text:                  "Turn left onto Broadway",
+		Type:                  ManeuverTypeTurn,
+		Modifier:              ManeuverModifierLeft,
+		Lanes:                 leg.Steps[1].Intersections[0].Lanes,
+	}}, guidance[0].Banners)
+
+	assert.Equal(t, []VoiceInstruction{
+		{DistanceAlongGeometry: 50, Announcement: "In 50 meters, you will arrive at your destination"},
+		{DistanceAlongGeometry: 25, Announcement: "You have arrived at your destination"},
+	}, guidance[1].Voice)
+	require.Len(t, guidance[1].Banners, 1)
+	assert.Nil(t, guidance[1].Banners[0].Lanes)
+
+	assert.Empty(t, guidance[2].Voice)
+	assert.Empty(t, guidance[2].Banners)
+}
+
+func TestLegGuidanceWithoutAnnotations(t *testing.T) {
+	leg := guidanceLeg()
+	leg.Annotation = Annotation{}
+	leg.Steps[0].Duration = 50 // 30 m/s
+
+	guidance := NewInstructor(EnglishInstructionCatalog()).LegGuidance(leg, InstructionOptions{})
+
+	var distances []float64
+	for _, v := range guidance[0].Voice {
+		distances = append(distances, v.DistanceAlongGeometry)
+	}
+	// the far announcement is beyond the step
+	assert.Equal(t, []float64{1500, 400, 150}, distances)
+	assert.Equal(t, "In 400 meters, turn left onto Broadway", guidance[0].Voice[1].Announcement)
+}
+
+func TestRouteGuidanceFromFixture(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+
+	guidance := NewInstructor(EnglishInstructionCatalog()).RouteGuidance(resp.Routes[0])
+	require.Len(t, guidance, 1)
+	require.Len(t, guidance[0], 4)
+
+	ramp := guidance[0][0]
+	assert.Equal(t, "Take the ramp on the right towards A 100: Hamburg", ramp.Banners[0].Text)
+	assert.NotEmpty(t, ramp.Banners[0].Lanes)
+	assert.Equal(t, "Take the ramp on the right towards A 100: Hamburg", ramp.Voice[len(ramp.Voice)-1].Announcement)
+}
+
+func TestFormatDistance(t *testing.T) {
+	instructor := NewInstructor(EnglishInstructionCatalog())
+
+	assert.Equal(t, "50 meters", instructor.formatDistance(50))
+	assert.Equal(t, "1 kilometer", instructor.formatDistance(1000))
+	assert.Equal(t, "1.5 kilometers", instructor.formatDistance(1500))
+}
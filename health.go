@@ -0,0 +1,121 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// defaultHealthProbes is used when Config.HealthProbes is not set.
+// osrm-routed serves a single dataset regardless of the profile name, and the nearest
+// service snaps any coordinate, so null island is good enough for a liveness check.
+var defaultHealthProbes = map[string]geo.Point{"car": {0, 0}}
+
+// ProbeResult represents a result of health check probe of a profile
+type ProbeResult struct {
+	Profile     string
+	Latency     time.Duration
+	DataVersion string
+	Err         error
+}
+
+// MarshalJSON encodes the probe result for health check handler
+func (p ProbeResult) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Profile     string  `json:"profile"`
+		Latency     float64 `json:"latency_ms"`
+		DataVersion string  `json:"data_version,omitempty"`
+		Error       string  `json:"error,omitempty"`
+	}{
+		Profile:     p.Profile,
+		Latency:     float64(p.Latency) / float64(time.Millisecond),
+		DataVersion: p.DataVersion,
+	}
+	if p.Err != nil {
+		v.Error = p.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// HealthStatus represents health of OSRM server
+type HealthStatus struct {
+	ServerURL string        `json:"server_url"`
+	Healthy   bool          `json:"healthy"`
+	Probes    []ProbeResult `json:"probes"`
+}
+
+// Err returns the first failed probe error
+func (s HealthStatus) Err() error {
+	for _, p := range s.Probes {
+		if p.Err != nil {
+			return p.Err
+		}
+	}
+	return nil
+}
+
+// HealthCheck queries the nearest service for every configured probe and reports their results.
+// See Config.HealthProbes for details.
+func (o OSRM) HealthCheck(ctx context.Context) HealthStatus {
+	probes := o.probes
+	if len(probes) == 0 {
+		probes = defaultHealthProbes
+	}
+
+	profiles := make([]string, 0, len(probes))
+	for profile := range probes {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	status := HealthStatus{ServerURL: o.client.serverURL, Healthy: true}
+	for _, profile := range profiles {
+		start := time.Now()
+		resp, err := o.Nearest(ctx, NearestRequest{
+			Profile:     profile,
+			Coordinates: NewGeometryFromPointSet(geo.PointSet{probes[profile]}),
+			Number:      1,
+		})
+		probe := ProbeResult{
+			Profile: profile,
+			Latency: time.Since(start),
+			Err:     err,
+		}
+		if err == nil {
+			probe.DataVersion = resp.DataVersion
+		} else {
+			status.Healthy = false
+		}
+		status.Probes = append(status.Probes, probe)
+	}
+	return status
+}
+
+// Ping checks that OSRM server responds to all configured probes
+func (o OSRM) Ping(ctx context.Context) error {
+	return o.HealthCheck(ctx).Err()
+}
+
+// NewHealthHandler creates a handler checking health of the given OSRM servers, e.g. for readiness probes.
+// It responds with 200 status code if all servers are healthy and 503 otherwise,
+// the body contains JSON encoded list of HealthStatus.
+func NewHealthHandler(servers ...*OSRM) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := http.StatusOK
+		statuses := make([]HealthStatus, len(servers))
+		for i, s := range servers {
+			statuses[i] = s.HealthCheck(r.Context())
+			if !statuses[i].Healthy {
+				code = http.StatusServiceUnavailable
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(statuses)
+	})
+}
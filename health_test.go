@@ -0,0 +1,83 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "number=1", r.URL.RawQuery)
+		if r.URL.Path == "/nearest/v1/foot/polyline(??)" {
+			_, _ = w.Write(fixturedJSON("invalid_query_response"))
+			return
+		}
+		_, _ = w.Write(fixturedJSON("nearest_response_full"))
+	}))
+	defer ts.Close()
+
+	osrm := NewWithConfig(Config{
+		ServerURL: ts.URL,
+		HealthProbes: map[string]geo.Point{
+			"foot": {0, 0},
+			"car":  {-73.994550, 40.735551},
+		},
+	})
+
+	status := osrm.HealthCheck(context.Background())
+
+	assert.Equal(t, []string{"/nearest/v1/car/polyline(edswF|`sbM)", "/nearest/v1/foot/polyline(??)"}, paths)
+	assert.False(t, status.Healthy)
+	assert.Equal(t, ts.URL, status.ServerURL)
+	require.Len(t, status.Probes, 2)
+	assert.Equal(t, "car", status.Probes[0].Profile)
+	assert.NoError(t, status.Probes[0].Err)
+	assert.True(t, status.Probes[0].Latency > 0)
+	assert.Equal(t, "foot", status.Probes[1].Profile)
+	assert.EqualError(t, status.Probes[1].Err, "InvalidQuery - Query string malformed close to position 28")
+	assert.Equal(t, status.Probes[1].Err, osrm.Ping(context.Background()))
+}
+
+func TestHealthHandler(t *testing.T) {
+	ts := httptest.NewServer(fixturedHTTPHandler("nearest_response_full", func(path, query string) {
+		assert.Equal(t, "/nearest/v1/car/polyline(??)", path)
+	}))
+	defer ts.Close()
+
+	healthy := NewFromURL(ts.URL)
+	unhealthy := NewFromURL("http://127.0.0.1:0")
+
+	t.Run("healthy", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHealthHandler(healthy).ServeHTTP(w, httptest.NewRequest("GET", "/ready", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var statuses []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+		require.Len(t, statuses, 1)
+		assert.Equal(t, true, statuses[0]["healthy"])
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHealthHandler(healthy, unhealthy).ServeHTTP(w, httptest.NewRequest("GET", "/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var statuses []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+		require.Len(t, statuses, 2)
+		assert.Equal(t, false, statuses[1]["healthy"])
+		probe := statuses[1]["probes"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "car", probe["profile"])
+		assert.NotEmpty(t, probe["error"])
+	})
+}
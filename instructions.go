@@ -0,0 +1,284 @@
+package osrm
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Keys of instruction phrases. Phrases of roundabouts and rotaries are additionally selected
+// by the exit number and the rotary name, e.g. "exit_name" or "rotary_exit_destination".
+const (
+	InstructionPhraseDefault         = "default"
+	InstructionPhraseName            = "name"
+	InstructionPhraseDestination     = "destination"
+	InstructionPhraseExit            = "exit"
+	InstructionPhraseExitDestination = "exit_destination"
+	InstructionPhraseNamed           = "named"
+	InstructionPhraseUpcoming        = "upcoming"
+)
+
+// InstructionPhrases maps phrase keys to instruction templates. Templates can contain the placeholders
+// {way_name}, {destination}, {exit}, {exit_number}, {rotary_name}, {lane_instruction}, {modifier},
+// {direction}, {nth} and {waypoint_name}.
+type InstructionPhrases map[string]string
+
+// InstructionCatalog represents translations of turn-by-turn instructions to a language.
+// It follows the structure of osrm-text-instructions translations.
+type InstructionCatalog struct {
+	Language string
+	// Ordinals are ordinal numbers starting from the first
+	Ordinals []string
+	// Directions are translations of "north", "northeast", "east", "southeast", "south", "southwest", "west" and "northwest"
+	Directions map[string]string
+	Modifiers  map[ManeuverModifier]string
+	// Lanes are translations of lane patterns, e.g. "xo" is "Keep right".
+	// The pattern has "o" for valid lanes and "x" for other lanes with consecutive duplicates collapsed.
+	Lanes map[string]string
+	// WayNameWithRef is the template of a way having both the name and the ref
+	WayNameWithRef string
+	// CapitalizeFirstLetter makes instructions start with a capital letter,
+	// and instructions embedded into announcements start with a lowercase letter
+	CapitalizeFirstLetter bool
+	// InDistance is the template of an announcement of a maneuver ahead with {distance} and {instruction} placeholders
+	InDistance string
+	// Meters, Kilometer and Kilometers are templates of distances with {distance} placeholder
+	Meters, Kilometer, Kilometers string
+	// Instructions are phrases by maneuver type and modifier, the empty modifier holds the default phrases
+	Instructions map[ManeuverType]map[ManeuverModifier]InstructionPhrases
+}
+
+// InstructionOptions represents the context of a step in the route
+type InstructionOptions struct {
+	// LegIndex and LegCount are used to tell intermediate destinations from the last one on arrival
+	LegIndex, LegCount int
+	// WaypointName is the name of the destination used on arrival
+	WaypointName string
+	// Upcoming selects phrases announcing the maneuver in advance if the catalog has them
+	Upcoming bool
+}
+
+// Instructor generates human-readable turn-by-turn instructions from route steps,
+// it is a port of osrm-text-instructions
+type Instructor struct {
+	catalog InstructionCatalog
+}
+
+// NewInstructor creates an instructor with the given catalog, e.g. EnglishInstructionCatalog()
+func NewInstructor(catalog InstructionCatalog) Instructor {
+	return Instructor{catalog: catalog}
+}
+
+// Language returns the language of instructions
+func (i Instructor) Language() string {
+	return i.catalog.Language
+}
+
+// LegInstructions returns instructions for all the steps of the leg
+func (i Instructor) LegInstructions(leg RouteLeg, opts InstructionOptions) []string {
+	instructions := make([]string, len(leg.Steps))
+	for j, s := range leg.Steps {
+		instructions[j] = i.Compile(s, opts)
+	}
+	return instructions
+}
+
+// Compile returns the instruction for the step, e.g. "Turn left onto Broadway".
+// Unknown maneuver types are described as turns.
+func (i Instructor) Compile(step RouteStep, opts InstructionOptions) string {
+	m := step.Maneuver
+
+	phrases := i.phrases(m.Type, m.Modifier)
+	wayName := i.wayName(step)
+	destination := firstDestination(step.Destinations)
+	exit := strings.Split(step.Exits, ";")[0]
+
+	var key string
+	switch {
+	case opts.Upcoming && phrases[InstructionPhraseUpcoming] != "":
+		key = InstructionPhraseUpcoming
+	case m.Type == ManeuverTypeRotary || m.Type == ManeuverTypeRoundabout:
+		key = roundaboutPhraseKey(step, wayName, destination)
+	case destination != "" && exit != "" && phrases[InstructionPhraseExitDestination] != "":
+		key = InstructionPhraseExitDestination
+	case destination != "" && phrases[InstructionPhraseDestination] != "":
+		key = InstructionPhraseDestination
+	case exit != "" && phrases[InstructionPhraseExit] != "":
+		key = InstructionPhraseExit
+	case wayName != "" && phrases[InstructionPhraseName] != "":
+		key = InstructionPhraseName
+	case opts.WaypointName != "" && phrases[InstructionPhraseNamed] != "":
+		key = InstructionPhraseNamed
+	}
+	template, ok := phrases[key]
+	if !ok {
+		template = phrases[InstructionPhraseDefault]
+	}
+
+	var laneInstruction string
+	if m.Type == ManeuverTypeUseLane {
+		laneInstruction, ok = i.catalog.Lanes[lanesPattern(step)]
+		if !ok {
+			template = i.phrases(ManeuverTypeContinue, ManeuverModifierStraight)[InstructionPhraseDefault]
+		}
+	}
+
+	var nth string
+	if opts.LegCount > 1 && opts.LegIndex < opts.LegCount-1 {
+		nth = i.ordinal(opts.LegIndex + 1)
+	}
+	exitNumber := i.ordinal(1)
+	if m.Exit != nil {
+		exitNumber = i.ordinal(int(*m.Exit))
+	}
+
+	instruction := strings.NewReplacer(
+		"{way_name}", wayName,
+		"{destination}", destination,
+		"{exit}", exit,
+		"{exit_number}", exitNumber,
+		"{rotary_name}", step.RotaryName,
+		"{lane_instruction}", laneInstruction,
+		"{modifier}", i.catalog.Modifiers[m.Modifier],
+		"{direction}", i.catalog.Directions[directionFromBearing(m.BearingAfter)],
+		"{nth}", nth,
+		"{waypoint_name}", opts.WaypointName,
+	).Replace(template)
+
+	// placeholders replaced with empty values leave extra spaces
+	instruction = strings.Join(strings.Fields(instruction), " ")
+	instruction = strings.Replace(instruction, " ,", ",", -1)
+	if i.catalog.CapitalizeFirstLetter {
+		instruction = capitalize(instruction)
+	}
+	return instruction
+}
+
+// phrases returns phrases of the maneuver falling back to the default modifier and to the turn maneuver
+func (i Instructor) phrases(t ManeuverType, modifier ManeuverModifier) InstructionPhrases {
+	byModifier, ok := i.catalog.Instructions[t]
+	if !ok {
+		byModifier = i.catalog.Instructions[ManeuverTypeTurn]
+	}
+	if phrases, ok := byModifier[modifier]; ok {
+		return phrases
+	}
+	return byModifier[""]
+}
+
+func (i Instructor) wayName(step RouteStep) string {
+	name, ref := step.Name, strings.Split(step.Ref, ";")[0]
+	if name == ref {
+		ref = ""
+	}
+	switch {
+	case name != "" && ref != "":
+		return strings.NewReplacer("{name}", name, "{ref}", ref).Replace(i.catalog.WayNameWithRef)
+	case name != "":
+		return name
+	default:
+		return ref
+	}
+}
+
+func (i Instructor) ordinal(n int) string {
+	if n >= 1 && n <= len(i.catalog.Ordinals) {
+		return i.catalog.Ordinals[n-1]
+	}
+	return strconv.Itoa(n)
+}
+
+// roundaboutPhraseKey selects phrases of roundabouts and rotaries by their name, exit and destination
+func roundaboutPhraseKey(step RouteStep, wayName, destination string) string {
+	var parts []string
+	if step.Maneuver.Type == ManeuverTypeRotary && step.RotaryName != "" {
+		parts = append(parts, "rotary")
+	}
+	if step.Maneuver.Exit != nil {
+		parts = append(parts, InstructionPhraseExit)
+	}
+	if destination != "" {
+		parts = append(parts, InstructionPhraseDestination)
+	} else if wayName != "" {
+		parts = append(parts, InstructionPhraseName)
+	}
+	if len(parts) == 0 {
+		return InstructionPhraseDefault
+	}
+	return strings.Join(parts, "_")
+}
+
+// firstDestination returns the first destination as "ref: name", e.g. "A 1: Berlin"
+func firstDestination(destinations string) string {
+	if destinations == "" {
+		return ""
+	}
+	parts := strings.SplitN(destinations, ": ", 2)
+	ref := strings.Split(parts[0], ",")[0]
+	if len(parts) == 1 {
+		return ref
+	}
+	name := strings.Split(parts[1], ",")[0]
+	if ref == "" {
+		return name
+	}
+	return ref + ": " + name
+}
+
+// lanesPattern describes lanes of the step maneuver with "o" for valid lanes and "x" for others
+func lanesPattern(step RouteStep) string {
+	if len(step.Intersections) == 0 {
+		return ""
+	}
+	var pattern []byte
+	for _, l := range step.Intersections[0].Lanes {
+		c := byte('x')
+		if l.Valid {
+			c = 'o'
+		}
+		if len(pattern) == 0 || pattern[len(pattern)-1] != c {
+			pattern = append(pattern, c)
+		}
+	}
+	return string(pattern)
+}
+
+func directionFromBearing(bearing float32) string {
+	switch {
+	case bearing <= 20:
+		return "north"
+	case bearing <= 70:
+		return "northeast"
+	case bearing <= 110:
+		return "east"
+	case bearing <= 160:
+		return "southeast"
+	case bearing <= 200:
+		return "south"
+	case bearing <= 250:
+		return "southwest"
+	case bearing <= 290:
+		return "west"
+	case bearing <= 340:
+		return "northwest"
+	default:
+		return "north"
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func decapitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
@@ -0,0 +1,373 @@
+package osrm
+
+// EnglishInstructionCatalog returns English translations of turn-by-turn instructions
+func EnglishInstructionCatalog() InstructionCatalog {
+	return InstructionCatalog{
+		Language: "en",
+		Ordinals: []string{"1st", "2nd", "3rd", "4th", "5th", "6th", "7th", "8th", "9th", "10th"},
+		Directions: map[string]string{
+			"north":     "north",
+			"northeast": "northeast",
+			"east":      "east",
+			"southeast": "southeast",
+			"south":     "south",
+			"southwest": "southwest",
+			"west":      "west",
+			"northwest": "northwest",
+		},
+		Modifiers: map[ManeuverModifier]string{
+			ManeuverModifierLeft:        "left",
+			ManeuverModifierRight:       "right",
+			ManeuverModifierSharpLeft:   "sharp left",
+			ManeuverModifierSharpRight:  "sharp right",
+			ManeuverModifierSlightLeft:  "slight left",
+			ManeuverModifierSlightRight: "slight right",
+			ManeuverModifierStraight:    "straight",
+			ManeuverModifierUTurn:       "U-turn",
+		},
+		Lanes: map[string]string{
+			"xo":  "Keep right",
+			"ox":  "Keep left",
+			"xox": "Keep in the middle",
+			"oxo": "Keep left or right",
+		},
+		WayNameWithRef:        "{name} ({ref})",
+		CapitalizeFirstLetter: true,
+		InDistance:            "In {distance}, {instruction}",
+		Meters:                "{distance} meters",
+		Kilometer:             "1 kilometer",
+		Kilometers:            "{distance} kilometers",
+		Instructions: map[ManeuverType]map[ManeuverModifier]InstructionPhrases{
+			ManeuverTypeArrive: {
+				"":                          arrivePhrases(""),
+				ManeuverModifierLeft:        arrivePhrases(", on the left"),
+				ManeuverModifierRight:       arrivePhrases(", on the right"),
+				ManeuverModifierSharpLeft:   arrivePhrases(", on the left"),
+				ManeuverModifierSharpRight:  arrivePhrases(", on the right"),
+				ManeuverModifierSlightLeft:  arrivePhrases(", on the left"),
+				ManeuverModifierSlightRight: arrivePhrases(", on the right"),
+				ManeuverModifierStraight:    arrivePhrases(", straight ahead"),
+			},
+			ManeuverTypeContinue: {
+				"": {
+					"default":     "Turn {modifier}",
+					"name":        "Turn {modifier} to stay on {way_name}",
+					"destination": "Turn {modifier} towards {destination}",
+					"exit":        "Turn {modifier} onto {way_name}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Continue straight",
+					"name":        "Continue straight to stay on {way_name}",
+					"destination": "Continue towards {destination}",
+				},
+				ManeuverModifierSharpLeft: {
+					"default":     "Make a sharp left",
+					"name":        "Make a sharp left to stay on {way_name}",
+					"destination": "Make a sharp left towards {destination}",
+				},
+				ManeuverModifierSharpRight: {
+					"default":     "Make a sharp right",
+					"name":        "Make a sharp right to stay on {way_name}",
+					"destination": "Make a sharp right towards {destination}",
+				},
+				ManeuverModifierSlightLeft: {
+					"default":     "Continue slightly left",
+					"name":        "Continue slightly left to stay on {way_name}",
+					"destination": "Continue slightly left towards {destination}",
+				},
+				ManeuverModifierSlightRight: {
+					"default":     "Continue slightly right",
+					"name":        "Continue slightly right to stay on {way_name}",
+					"destination": "Continue slightly right towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default":     "Make a U-turn",
+					"name":        "Make a U-turn to stay on {way_name}",
+					"destination": "Make a U-turn towards {destination}",
+				},
+			},
+			ManeuverTypeDepart: {
+				"": {
+					"default": "Head {direction}",
+					"name":    "Head {direction} on {way_name}",
+				},
+			},
+			ManeuverTypeEndOfRoad: {
+				"": {
+					"default":     "Turn {modifier}",
+					"name":        "Turn {modifier} onto {way_name}",
+					"destination": "Turn {modifier} towards {destination}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Continue straight",
+					"name":        "Continue straight onto {way_name}",
+					"destination": "Continue straight towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default":     "Make a U-turn at the end of the road",
+					"name":        "Make a U-turn onto {way_name} at the end of the road",
+					"destination": "Make a U-turn towards {destination} at the end of the road",
+				},
+			},
+			ManeuverTypeFork: {
+				"": {
+					"default":     "Keep {modifier} at the fork",
+					"name":        "Keep {modifier} onto {way_name}",
+					"destination": "Keep {modifier} towards {destination}",
+				},
+				ManeuverModifierSlightLeft: {
+					"default":     "Keep left at the fork",
+					"name":        "Keep left onto {way_name}",
+					"destination": "Keep left towards {destination}",
+				},
+				ManeuverModifierSlightRight: {
+					"default":     "Keep right at the fork",
+					"name":        "Keep right onto {way_name}",
+					"destination": "Keep right towards {destination}",
+				},
+				ManeuverModifierSharpLeft: {
+					"default":     "Take a sharp left at the fork",
+					"name":        "Take a sharp left onto {way_name}",
+					"destination": "Take a sharp left towards {destination}",
+				},
+				ManeuverModifierSharpRight: {
+					"default":     "Take a sharp right at the fork",
+					"name":        "Take a sharp right onto {way_name}",
+					"destination": "Take a sharp right towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default":     "Make a U-turn",
+					"name":        "Make a U-turn onto {way_name}",
+					"destination": "Make a U-turn towards {destination}",
+				},
+			},
+			ManeuverTypeMerge: {
+				"": {
+					"default":     "Merge {modifier}",
+					"name":        "Merge {modifier} onto {way_name}",
+					"destination": "Merge {modifier} towards {destination}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Merge",
+					"name":        "Merge onto {way_name}",
+					"destination": "Merge towards {destination}",
+				},
+				ManeuverModifierSlightLeft: {
+					"default":     "Merge left",
+					"name":        "Merge left onto {way_name}",
+					"destination": "Merge left towards {destination}",
+				},
+				ManeuverModifierSlightRight: {
+					"default":     "Merge right",
+					"name":        "Merge right onto {way_name}",
+					"destination": "Merge right towards {destination}",
+				},
+				ManeuverModifierSharpLeft: {
+					"default":     "Merge left",
+					"name":        "Merge left onto {way_name}",
+					"destination": "Merge left towards {destination}",
+				},
+				ManeuverModifierSharpRight: {
+					"default":     "Merge right",
+					"name":        "Merge right onto {way_name}",
+					"destination": "Merge right towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default":     "Make a U-turn",
+					"name":        "Make a U-turn onto {way_name}",
+					"destination": "Make a U-turn towards {destination}",
+				},
+			},
+			ManeuverTypeNewName: {
+				"": {
+					"default":     "Continue {modifier}",
+					"name":        "Continue {modifier} onto {way_name}",
+					"destination": "Continue {modifier} towards {destination}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Continue straight",
+					"name":        "Continue onto {way_name}",
+					"destination": "Continue towards {destination}",
+				},
+				ManeuverModifierSharpLeft: {
+					"default":     "Take a sharp left",
+					"name":        "Take a sharp left onto {way_name}",
+					"destination": "Take a sharp left towards {destination}",
+				},
+				ManeuverModifierSharpRight: {
+					"default":     "Take a sharp right",
+					"name":        "Take a sharp right onto {way_name}",
+					"destination": "Take a sharp right towards {destination}",
+				},
+				ManeuverModifierSlightLeft: {
+					"default":     "Continue slightly left",
+					"name":        "Continue slightly left onto {way_name}",
+					"destination": "Continue slightly left towards {destination}",
+				},
+				ManeuverModifierSlightRight: {
+					"default":     "Continue slightly right",
+					"name":        "Continue slightly right onto {way_name}",
+					"destination": "Continue slightly right towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default": "Make a U-turn",
+				},
+			},
+			ManeuverTypeNotification: {
+				"": {
+					"default":     "Continue {modifier}",
+					"name":        "Continue {modifier} onto {way_name}",
+					"destination": "Continue {modifier} towards {destination}",
+				},
+				ManeuverModifierUTurn: {
+					"default":     "Make a U-turn",
+					"name":        "Make a U-turn onto {way_name}",
+					"destination": "Make a U-turn towards {destination}",
+				},
+			},
+			ManeuverTypeOffRamp: {
+				"": {
+					"default":          "Take the ramp",
+					"name":             "Take the ramp onto {way_name}",
+					"destination":      "Take the ramp towards {destination}",
+					"exit":             "Take exit {exit}",
+					"exit_destination": "Take exit {exit} towards {destination}",
+				},
+				ManeuverModifierLeft:        offRampPhrases("left"),
+				ManeuverModifierRight:       offRampPhrases("right"),
+				ManeuverModifierSharpLeft:   offRampPhrases("left"),
+				ManeuverModifierSharpRight:  offRampPhrases("right"),
+				ManeuverModifierSlightLeft:  offRampPhrases("left"),
+				ManeuverModifierSlightRight: offRampPhrases("right"),
+			},
+			ManeuverTypeOnRamp: {
+				"": {
+					"default":     "Take the ramp",
+					"name":        "Take the ramp onto {way_name}",
+					"destination": "Take the ramp towards {destination}",
+				},
+				ManeuverModifierLeft:        onRampPhrases("left"),
+				ManeuverModifierRight:       onRampPhrases("right"),
+				ManeuverModifierSharpLeft:   onRampPhrases("left"),
+				ManeuverModifierSharpRight:  onRampPhrases("right"),
+				ManeuverModifierSlightLeft:  onRampPhrases("left"),
+				ManeuverModifierSlightRight: onRampPhrases("right"),
+			},
+			ManeuverTypeRotary: {
+				"": {
+					"default":                 "Enter the traffic circle",
+					"name":                    "Enter the traffic circle and exit onto {way_name}",
+					"destination":             "Enter the traffic circle and exit towards {destination}",
+					"exit":                    "Enter the traffic circle and take the {exit_number} exit",
+					"exit_name":               "Enter the traffic circle and take the {exit_number} exit onto {way_name}",
+					"exit_destination":        "Enter the traffic circle and take the {exit_number} exit towards {destination}",
+					"rotary":                  "Enter {rotary_name}",
+					"rotary_name":             "Enter {rotary_name} and exit onto {way_name}",
+					"rotary_destination":      "Enter {rotary_name} and exit towards {destination}",
+					"rotary_exit":             "Enter {rotary_name} and take the {exit_number} exit",
+					"rotary_exit_name":        "Enter {rotary_name} and take the {exit_number} exit onto {way_name}",
+					"rotary_exit_destination": "Enter {rotary_name} and take the {exit_number} exit towards {destination}",
+				},
+			},
+			ManeuverTypeRoundabout: {
+				"": {
+					"default":          "Enter the traffic circle",
+					"name":             "Enter the traffic circle and exit onto {way_name}",
+					"destination":      "Enter the traffic circle and exit towards {destination}",
+					"exit":             "Enter the traffic circle and take the {exit_number} exit",
+					"exit_name":        "Enter the traffic circle and take the {exit_number} exit onto {way_name}",
+					"exit_destination": "Enter the traffic circle and take the {exit_number} exit towards {destination}",
+				},
+			},
+			ManeuverTypeRoundaboutTurn: {
+				"": {
+					"default":     "Make a {modifier}",
+					"name":        "Make a {modifier} onto {way_name}",
+					"destination": "Make a {modifier} towards {destination}",
+				},
+				ManeuverModifierLeft: {
+					"default":     "Turn left",
+					"name":        "Turn left onto {way_name}",
+					"destination": "Turn left towards {destination}",
+				},
+				ManeuverModifierRight: {
+					"default":     "Turn right",
+					"name":        "Turn right onto {way_name}",
+					"destination": "Turn right towards {destination}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Continue straight",
+					"name":        "Continue straight onto {way_name}",
+					"destination": "Continue straight towards {destination}",
+				},
+			},
+			ManeuverTypeExitRoundabout: {
+				"": {
+					"default":     "Exit the traffic circle",
+					"name":        "Exit the traffic circle onto {way_name}",
+					"destination": "Exit the traffic circle towards {destination}",
+				},
+			},
+			ManeuverTypeExitRotary: {
+				"": {
+					"default":     "Exit the traffic circle",
+					"name":        "Exit the traffic circle onto {way_name}",
+					"destination": "Exit the traffic circle towards {destination}",
+				},
+			},
+			ManeuverTypeTurn: {
+				"": {
+					"default":     "Make a {modifier}",
+					"name":        "Make a {modifier} onto {way_name}",
+					"destination": "Make a {modifier} towards {destination}",
+				},
+				ManeuverModifierLeft: {
+					"default":     "Turn left",
+					"name":        "Turn left onto {way_name}",
+					"destination": "Turn left towards {destination}",
+				},
+				ManeuverModifierRight: {
+					"default":     "Turn right",
+					"name":        "Turn right onto {way_name}",
+					"destination": "Turn right towards {destination}",
+				},
+				ManeuverModifierStraight: {
+					"default":     "Go straight",
+					"name":        "Go straight onto {way_name}",
+					"destination": "Go straight towards {destination}",
+				},
+			},
+			ManeuverTypeUseLane: {
+				"": {
+					"default": "{lane_instruction}",
+				},
+			},
+		},
+	}
+}
+
+func arrivePhrases(side string) InstructionPhrases {
+	return InstructionPhrases{
+		"default":  "You have arrived at your {nth} destination" + side,
+		"named":    "You have arrived at {waypoint_name}" + side,
+		"upcoming": "You will arrive at your {nth} destination" + side,
+	}
+}
+
+func offRampPhrases(side string) InstructionPhrases {
+	return InstructionPhrases{
+		"default":          "Take the ramp on the " + side,
+		"name":             "Take the ramp on the " + side + " onto {way_name}",
+		"destination":      "Take the ramp on the " + side + " towards {destination}",
+		"exit":             "Take exit {exit} on the " + side,
+		"exit_destination": "Take exit {exit} on the " + side + " towards {destination}",
+	}
+}
+
+func onRampPhrases(side string) InstructionPhrases {
+	return InstructionPhrases{
+		"default":     "Take the ramp on the " + side,
+		"name":        "Take the ramp on the " + side + " onto {way_name}",
+		"destination": "Take the ramp on the " + side + " towards {destination}",
+	}
+}
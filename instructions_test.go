@@ -0,0 +1,186 @@
+package osrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstructionsFromFixtures(t *testing.T) {
+	instructor := NewInstructor(EnglishInstructionCatalog())
+
+	cases := []struct {
+		fixture  string
+		expected [][]string
+	}{
+		{
+			fixture: "route_response_full",
+			expected: [][]string{
+				{
+					"Head south",
+					"Turn right",
+					"Turn right",
+					"Turn left",
+					"Turn right",
+					"Turn right",
+					"You have arrived at your 1st destination",
+				},
+				{
+					"Head southeast",
+					"You have arrived at your destination",
+				},
+			},
+		},
+		{
+			fixture: "route_response_steps",
+			expected: [][]string{
+				{
+					"Head north on Friedrichstraße (B 96)",
+					"Take the ramp on the right towards A 100: Hamburg",
+					"Enter Rosenthaler Platz and take the 2nd exit onto Torstraße",
+					"You have arrived at your destination",
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.fixture, func(t *testing.T) {
+			var resp RouteResponse
+			fixturedResponse(t, c.fixture, &resp)
+
+			legs := resp.Routes[0].Legs
+			var actual [][]string
+			for i, l := range legs {
+				actual = append(actual, instructor.LegInstructions(l, InstructionOptions{LegIndex: i, LegCount: len(legs)}))
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestInstructionCompile(t *testing.T) {
+	instructor := NewInstructor(EnglishInstructionCatalog())
+	exit := uint32(3)
+
+	cases := []struct {
+		name     string
+		step     RouteStep
+		opts     InstructionOptions
+		expected string
+	}{
+		{
+			name:     "turn onto way",
+			step:     RouteStep{Name: "Broadway", Maneuver: StepManeuver{Type: ManeuverTypeTurn, Modifier: ManeuverModifierLeft}},
+			expected: "Turn left onto Broadway",
+		},
+		{
+			name:     "turn with modifier placeholder",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeTurn, Modifier: ManeuverModifierSharpRight}},
+			expected: "Make a sharp right",
+		},
+		{
+			name:     "same name and ref",
+			step:     RouteStep{Name: "A 1", Ref: "A 1", Maneuver: StepManeuver{Type: ManeuverTypeMerge, Modifier: ManeuverModifierSlightLeft}},
+			expected: "Merge left onto A 1",
+		},
+		{
+			name:     "ref only",
+			step:     RouteStep{Ref: "I 95;US 1", Maneuver: StepManeuver{Type: ManeuverTypeFork, Modifier: ManeuverModifierSlightRight}},
+			expected: "Keep right onto I 95",
+		},
+		{
+			name: "off ramp with exit and destination",
+			step: RouteStep{
+				Exits:        "12A;12B",
+				Destinations: "Downtown, Airport",
+				Maneuver:     StepManeuver{Type: ManeuverTypeOffRamp, Modifier: ManeuverModifierSlightRight},
+			},
+			expected: "Take exit 12A on the right towards Downtown",
+		},
+		{
+			name:     "off ramp with exit",
+			step:     RouteStep{Exits: "7", Maneuver: StepManeuver{Type: ManeuverTypeOffRamp}},
+			expected: "Take exit 7",
+		},
+		{
+			name:     "roundabout",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeRoundabout, Exit: &exit}},
+			expected: "Enter the traffic circle and take the 3rd exit",
+		},
+		{
+			name:     "rotary without exit",
+			step:     RouteStep{Name: "Main Street", Maneuver: StepManeuver{Type: ManeuverTypeRotary}},
+			expected: "Enter the traffic circle and exit onto Main Street",
+		},
+		{
+			name:     "continue u-turn",
+			step:     RouteStep{Name: "Broadway", Maneuver: StepManeuver{Type: ManeuverTypeContinue, Modifier: ManeuverModifierUTurn}},
+			expected: "Make a U-turn to stay on Broadway",
+		},
+		{
+			name:     "end of road",
+			step:     RouteStep{Destinations: "A 1: Berlin", Maneuver: StepManeuver{Type: ManeuverTypeEndOfRoad, Modifier: ManeuverModifierRight}},
+			expected: "Turn right towards A 1: Berlin",
+		},
+		{
+			name:     "depart direction",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeDepart, BearingAfter: 265}},
+			expected: "Head west",
+		},
+		{
+			name: "use lane",
+			step: RouteStep{
+				Maneuver:      StepManeuver{Type: ManeuverTypeUseLane, Modifier: ManeuverModifierStraight},
+				Intersections: []Intersection{{Lanes: []Lane{{Valid: false}, {Valid: true}, {Valid: true}}}},
+			},
+			expected: "Keep right",
+		},
+		{
+			name:     "use lane without lanes",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeUseLane, Modifier: ManeuverModifierStraight}},
+			expected: "Continue straight",
+		},
+		{
+			name:     "unknown maneuver",
+			step:     RouteStep{Name: "Elm Street", Maneuver: StepManeuver{Type: "teleport", Modifier: ManeuverModifierRight}},
+			expected: "Turn right onto Elm Street",
+		},
+		{
+			name:     "arrive at named waypoint",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeArrive, Modifier: ManeuverModifierLeft}},
+			opts:     InstructionOptions{WaypointName: "Central Station"},
+			expected: "You have arrived at Central Station, on the left",
+		},
+		{
+			name:     "arrive at intermediate destination",
+			step:     RouteStep{Maneuver: StepManeuver{Type: ManeuverTypeArrive}},
+			opts:     InstructionOptions{LegIndex: 1, LegCount: 3},
+			expected: "You have arrived at your 2nd destination",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, instructor.Compile(c.step, c.opts))
+		})
+	}
+}
+
+func TestInstructionCustomCatalog(t *testing.T) {
+	catalog := EnglishInstructionCatalog()
+	catalog.Language = "de"
+	catalog.Modifiers[ManeuverModifierLeft] = "links"
+	catalog.Instructions[ManeuverTypeTurn] = map[ManeuverModifier]InstructionPhrases{
+		"": {
+			InstructionPhraseDefault: "{modifier} abbiegen",
+			InstructionPhraseName:    "{modifier} abbiegen auf {way_name}",
+		},
+	}
+
+	instructor := NewInstructor(catalog)
+	step := RouteStep{Name: "Torstraße", Maneuver: StepManeuver{Type: ManeuverTypeTurn, Modifier: ManeuverModifierLeft}}
+
+	assert.Equal(t, "de", instructor.Language())
+	assert.Equal(t, "Links abbiegen auf Torstraße", instructor.Compile(step, InstructionOptions{}))
+	// the shipped catalog isn't affected
+	assert.Equal(t, "Turn left onto Torstraße", NewInstructor(EnglishInstructionCatalog()).Compile(step, InstructionOptions{}))
+}
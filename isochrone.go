@@ -0,0 +1,314 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+const (
+	defaultIsochroneGridSize  = 20
+	defaultIsochroneMaxSpeed  = 60 / 3.6 // 60 km/h in m/s
+	defaultIsochroneBatchSize = 99       // OSRM limits table requests to 100 coordinates by default
+	metersPerDegree           = 111320.0
+)
+
+// IsochroneConfig represents options of isochrone sampling
+type IsochroneConfig struct {
+	// GridSize is the number of grid cells along each side of the sampled square.
+	// 20 cells will be used as default if not set.
+	GridSize int
+	// MaxSpeed in meters per second bounds the sampled area: the square spans the distance
+	// driven at this speed during the longest threshold in every direction. 60 km/h will be used as default if not set.
+	MaxSpeed float64
+	// BatchSize is the number of sampled points evaluated by a single table request.
+	// 99 points will be used as default if not set.
+	BatchSize int
+}
+
+// Isochrone builds polygons reachable from the origin within the thresholds with the default config.
+// See IsochroneWithConfig for details.
+func (o OSRM) Isochrone(ctx context.Context, profile string, origin geo.Point, thresholds []time.Duration) (*geojson.FeatureCollection, error) {
+	return o.IsochroneWithConfig(ctx, profile, origin, thresholds, IsochroneConfig{})
+}
+
+// IsochroneWithConfig builds polygons reachable from the origin within the thresholds.
+// Durations from the origin are sampled on a grid with table requests and contoured with marching squares.
+// The result has a MultiPolygon feature per threshold in ascending order with "threshold" property in seconds.
+func (o OSRM) IsochroneWithConfig(ctx context.Context, profile string, origin geo.Point, thresholds []time.Duration, cfg IsochroneConfig) (*geojson.FeatureCollection, error) {
+	if cfg.GridSize <= 0 {
+		cfg.GridSize = defaultIsochroneGridSize
+	}
+	if cfg.MaxSpeed <= 0 {
+		cfg.MaxSpeed = defaultIsochroneMaxSpeed
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultIsochroneBatchSize
+	}
+
+	sorted := make([]time.Duration, len(thresholds))
+	copy(sorted, thresholds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	fc := geojson.NewFeatureCollection()
+	if len(sorted) == 0 {
+		return fc, nil
+	}
+
+	grid := newIsochroneGrid(origin, sorted[len(sorted)-1].Seconds()*cfg.MaxSpeed, cfg.GridSize)
+	if err := o.sampleIsochroneGrid(ctx, profile, grid, cfg.BatchSize); err != nil {
+		return nil, err
+	}
+
+	for _, t := range sorted {
+		f := geojson.NewMultiPolygonFeature(grid.contour(t.Seconds())...)
+		f.SetProperty("threshold", t.Seconds())
+		f.SetProperty("profile", profile)
+		fc.AddFeature(f)
+	}
+	return fc, nil
+}
+
+// isochroneGrid is a square grid of nodes centered at the origin with durations from the origin.
+// Nodes outside of the grid are considered unreachable, which makes all the contours closed.
+type isochroneGrid struct {
+	origin    geo.Point
+	size      int // number of cells along each side
+	cellLng   float64
+	cellLat   float64
+	durations [][]float64 // by row from south to north and column from west to east
+}
+
+func newIsochroneGrid(origin geo.Point, radius float64, size int) *isochroneGrid {
+	cell := 2 * radius / float64(size)
+	g := &isochroneGrid{
+		origin:    origin,
+		size:      size,
+		cellLat:   cell / metersPerDegree,
+		cellLng:   cell / (metersPerDegree * math.Cos(origin.Lat()*math.Pi/180)),
+		durations: make([][]float64, size+1),
+	}
+	for i := range g.durations {
+		g.durations[i] = make([]float64, size+1)
+	}
+	return g
+}
+
+// location returns the location of the grid point, fractional coordinates are allowed
+func (g *isochroneGrid) location(row, col float64) geo.Point {
+	half := float64(g.size) / 2
+	return geo.Point{
+		g.origin.Lng() + (col-half)*g.cellLng,
+		g.origin.Lat() + (row-half)*g.cellLat,
+	}
+}
+
+func (g *isochroneGrid) duration(row, col int) float64 {
+	if row < 0 || col < 0 || row > g.size || col > g.size {
+		return math.Inf(1)
+	}
+	return g.durations[row][col]
+}
+
+func (o OSRM) sampleIsochroneGrid(ctx context.Context, profile string, g *isochroneGrid, batchSize int) error {
+	var nodes [][2]int
+	var points geo.PointSet
+	for row := 0; row <= g.size; row++ {
+		for col := 0; col <= g.size; col++ {
+			nodes = append(nodes, [2]int{row, col})
+			points = append(points, g.location(float64(row), float64(col)))
+		}
+	}
+
+	// a table request of the batch has the origin along with the batch points
+	durations, _, err := o.tableMatrices(ctx, profile, batchSize+1, geo.PointSet{g.origin}, points, false)
+	if err != nil {
+		return err
+	}
+	for i, n := range nodes {
+		g.durations[n[0]][n[1]] = durations[0][i]
+	}
+	return nil
+}
+
+// isochroneEdge identifies a point on a grid edge: the edge starts at the node (row, col)
+// and goes to the east if horizontal or to the north otherwise
+type isochroneEdge struct {
+	row, col   int
+	horizontal bool
+}
+
+// marching squares segments by cell case, corners are numbered as bl=1, br=2, tr=4, tl=8
+// and edges as bottom=0, right=1, top=2, left=3
+var marchingSquaresSegments = [16][][2]int{
+	0:  nil,
+	1:  {{3, 0}},
+	2:  {{0, 1}},
+	3:  {{3, 1}},
+	4:  {{2, 1}},
+	5:  nil, // saddle
+	6:  {{2, 0}},
+	7:  {{3, 2}},
+	8:  {{3, 2}},
+	9:  {{2, 0}},
+	10: nil, // saddle
+	11: {{2, 1}},
+	12: {{3, 1}},
+	13: {{0, 1}},
+	14: {{3, 0}},
+	15: nil,
+}
+
+// contour returns polygons of the area reachable within the threshold in GeoJSON coordinates
+func (g *isochroneGrid) contour(threshold float64) [][][][]float64 {
+	neighbors := make(map[isochroneEdge][]isochroneEdge)
+	link := func(a, b isochroneEdge) {
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+
+	// cells around the grid are included to close contours touching the grid border
+	for row := -1; row <= g.size; row++ {
+		for col := -1; col <= g.size; col++ {
+			bl, br := g.duration(row, col), g.duration(row, col+1)
+			tl, tr := g.duration(row+1, col), g.duration(row+1, col+1)
+
+			c := 0
+			for bit, d := range [4]float64{bl, br, tr, tl} {
+				if d <= threshold {
+					c |= 1 << uint(bit)
+				}
+			}
+
+			edges := [4]isochroneEdge{
+				{row, col, true},
+				{row, col + 1, false},
+				{row + 1, col, true},
+				{row, col, false},
+			}
+			segments := marchingSquaresSegments[c]
+			if c == 5 || c == 10 {
+				center := (bl + br + tr + tl) / 4
+				centerInside := center <= threshold
+				if (c == 5) == centerInside {
+					segments = [][2]int{{3, 2}, {0, 1}} // cut off tl and br corners
+				} else {
+					segments = [][2]int{{2, 1}, {3, 0}} // cut off tr and bl corners
+				}
+			}
+			for _, s := range segments {
+				link(edges[s[0]], edges[s[1]])
+			}
+		}
+	}
+
+	// rings are traced in the grid order to make the result deterministic
+	starts := make([]isochroneEdge, 0, len(neighbors))
+	for e := range neighbors {
+		starts = append(starts, e)
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		a, b := starts[i], starts[j]
+		if a.row != b.row {
+			return a.row < b.row
+		}
+		if a.col != b.col {
+			return a.col < b.col
+		}
+		return a.horizontal && !b.horizontal
+	})
+
+	var rings [][][]float64
+	visited := make(map[isochroneEdge]bool, len(neighbors))
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		var ring [][]float64
+		prev, cur := start, start
+		for {
+			visited[cur] = true
+			ring = append(ring, g.edgePoint(cur, threshold))
+			next := neighbors[cur][0]
+			if next == prev && len(neighbors[cur]) > 1 {
+				next = neighbors[cur][1]
+			}
+			prev, cur = cur, next
+			if cur == start {
+				break
+			}
+		}
+		ring = append(ring, ring[0])
+		rings = append(rings, ring)
+	}
+	return nestRings(rings)
+}
+
+// edgePoint interpolates the location on the edge where the duration reaches the threshold
+func (g *isochroneGrid) edgePoint(e isochroneEdge, threshold float64) []float64 {
+	row, col := e.row, e.col
+	endRow, endCol := row+1, col
+	if e.horizontal {
+		endRow, endCol = row, col+1
+	}
+
+	a, b := g.duration(row, col), g.duration(endRow, endCol)
+	t := 0.5
+	if !math.IsInf(a, 1) && !math.IsInf(b, 1) && a != b {
+		t = (threshold - a) / (b - a)
+	}
+
+	p := g.location(float64(row)+t*float64(endRow-row), float64(col)+t*float64(endCol-col))
+	return []float64{p.Lng(), p.Lat()}
+}
+
+// nestRings groups rings into polygons, rings inside of an odd number of other rings are holes
+func nestRings(rings [][][]float64) [][][][]float64 {
+	sort.Slice(rings, func(i, j int) bool { return ringArea(rings[i]) > ringArea(rings[j]) })
+
+	var polygons [][][][]float64
+	var outers []int // polygon index of outer rings
+	for i, ring := range rings {
+		depth, parent := 0, -1
+		for j := 0; j < i; j++ {
+			if ringContains(rings[j], ring[0]) {
+				depth++
+				parent = j
+			}
+		}
+		if depth%2 == 0 {
+			polygons = append(polygons, [][][]float64{ring})
+			outers = append(outers, len(polygons)-1)
+			continue
+		}
+		outers = append(outers, -1)
+		if p := outers[parent]; p >= 0 {
+			polygons[p] = append(polygons[p], ring)
+		}
+	}
+	return polygons
+}
+
+func ringArea(ring [][]float64) float64 {
+	var area float64
+	for i := 1; i < len(ring); i++ {
+		area += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+	}
+	return math.Abs(area / 2)
+}
+
+// ringContains checks whether the point is inside of the ring with the ray casting
+func ringContains(ring [][]float64, p []float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a[1] > p[1]) != (b[1] > p[1]) && p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
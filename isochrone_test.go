@@ -0,0 +1,102 @@
+package osrm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isochroneHandler is matrixHandler checking that durations are requested from the origin only
+func isochroneHandler(t *testing.T, requests *int, unreachable float64) http.HandlerFunc {
+	matrix := matrixHandler(t, requests, unreachable)
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0", r.URL.Query().Get("sources"))
+		matrix(w, r)
+	}
+}
+
+func TestIsochrone(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(isochroneHandler(t, &requests, 180))
+	defer ts.Close()
+
+	origin := geo.Point{13.388860, 52.517037}
+	fc, err := NewFromURL(ts.URL).IsochroneWithConfig(context.Background(), "car", origin, []time.Duration{5 * time.Minute, 2 * time.Minute}, IsochroneConfig{
+		MaxSpeed: 15,
+	})
+	require.NoError(t, err)
+
+	// 21x21 grid points are evaluated by 99 points
+	assert.Equal(t, 5, requests)
+	require.Len(t, fc.Features, 2)
+	assert.Equal(t, float64(120), fc.Features[0].Properties["threshold"])
+	assert.Equal(t, float64(300), fc.Features[1].Properties["threshold"])
+
+	polygons := fc.Features[1].Geometry.MultiPolygon
+	require.Len(t, polygons, 1)
+	require.Len(t, polygons[0], 1)
+	ring := polygons[0][0]
+	assert.Equal(t, ring[0], ring[len(ring)-1])
+
+	// the 5 minutes contour is a circle of 3 km around the origin
+	for _, p := range ring {
+		assert.InDelta(t, 3000, origin.GeoDistanceFrom(&geo.Point{p[0], p[1]}, true), 150)
+	}
+	assert.True(t, ringContains(ring, []float64{origin.Lng(), origin.Lat()}))
+	assert.False(t, ringContains(fc.Features[0].Geometry.MultiPolygon[0][0], []float64{origin.Lng(), origin.Lat() + 0.02}))
+	assert.True(t, ringContains(ring, []float64{origin.Lng(), origin.Lat() + 0.02}))
+
+	bytes, err := fc.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(bytes), `"type":"MultiPolygon"`)
+}
+
+func TestIsochroneWithUnreachablePoints(t *testing.T) {
+	var requests int
+	origin := geo.Point{13.388860, 52.517037}
+	ts := httptest.NewServer(isochroneHandler(t, &requests, origin.Lng()+0.01))
+	defer ts.Close()
+
+	fc, err := NewFromURL(ts.URL).IsochroneWithConfig(context.Background(), "car", origin, []time.Duration{5 * time.Minute}, IsochroneConfig{
+		MaxSpeed:  15,
+		BatchSize: 200,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	ring := fc.Features[0].Geometry.MultiPolygon[0][0]
+	for _, p := range ring {
+		assert.True(t, p[0] < origin.Lng()+0.02)
+	}
+	assert.False(t, ringContains(ring, []float64{origin.Lng() + 0.03, origin.Lat()}))
+	assert.True(t, ringContains(ring, []float64{origin.Lng() - 0.03, origin.Lat()}))
+}
+
+func TestIsochroneContourWithHole(t *testing.T) {
+	g := newIsochroneGrid(geo.Point{0, 0}, 2000, 4)
+	for row := range g.durations {
+		for col := range g.durations[row] {
+			g.durations[row][col] = 100
+		}
+	}
+	g.durations[2][2] = 1000
+
+	polygons := g.contour(500)
+	require.Len(t, polygons, 1)
+	require.Len(t, polygons[0], 2)
+	hole := polygons[0][1]
+	assert.True(t, ringContains(hole, []float64{0, 0}))
+	assert.True(t, ringArea(hole) < ringArea(polygons[0][0]))
+}
+
+func TestIsochroneWithoutThresholds(t *testing.T) {
+	fc, err := NewFromURL("http://127.0.0.1:0").Isochrone(context.Background(), "car", geo.Point{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, fc.Features)
+}
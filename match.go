@@ -16,6 +16,7 @@ type MatchRequest struct {
 	Overview    Overview
 	Gaps        Gaps
 	Geometries  Geometries
+	Format      Format
 }
 
 // MatchResponse represents a response from the match method
@@ -52,10 +53,12 @@ func (r MatchRequest) request() *request {
 	}
 
 	return &request{
-		profile: r.Profile,
-		coords:  r.Coordinates,
-		service: "match",
-		options: options,
+		profile:    r.Profile,
+		coords:     r.Coordinates,
+		service:    "match",
+		format:     r.Format,
+		geometries: r.Geometries,
+		options:    options,
 	}
 }
 
@@ -66,6 +69,8 @@ type Tracepoint struct {
 	MatchingIndex     int       `json:"matchings_index"`
 	AlternativesCount int       `json:"alternatives_count"`
 	Hint              string    `json:"hint"`
+	Name              string    `json:"name"`
+	Distance          float64   `json:"distance"`
 }
 
 func matcherOptions(options options, tidy Tidy, gaps Gaps) options {
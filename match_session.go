@@ -0,0 +1,250 @@
+package osrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+const (
+	defaultMatchSessionWindowSize = 20
+	defaultMatchSessionOverlap    = 5
+	defaultMatchSessionRadius     = 5.0 // OSRM default GPS precision in meters
+)
+
+// MatchSessionConfig represents options of a map matching session
+type MatchSessionConfig struct {
+	// Profile is OSRM profile used for matching.
+	Profile string
+	// WindowSize is the number of points sent in a single match request.
+	// 20 points will be used as default if not set.
+	WindowSize int
+	// Overlap is the number of points kept unconfirmed at the end of a window to be matched
+	// again with the next points, and the number of confirmed points used as a context at the start of the next window.
+	// WindowSize should be greater than doubled overlap. 5 points will be used as default if not set.
+	Overlap int
+	// MinConfidence is the minimal confidence of a matching, positions of less confident matchings are reported as unmatched.
+	MinConfidence float64
+	// Gaps defines how OSRM handles gaps in the trace. Gaps are split by default.
+	Gaps Gaps
+	// Tidy allows OSRM to remove points too close to each other.
+	Tidy Tidy
+	// Buffer is the size of positions channel buffer.
+	Buffer int
+}
+
+// MatchPoint represents a GPS point added to a map matching session
+type MatchPoint struct {
+	Location geo.Point
+	// Timestamp is UNIX time in seconds. Timestamps are sent only if they are set for all points of a window.
+	Timestamp int64
+	// Radius is the GPS accuracy in meters. OSRM default of 5 meters is used if not set.
+	Radius float64
+	// Bearing is optional bearing of the vehicle.
+	Bearing *Bearing
+}
+
+// MatchedPosition represents a confirmed result of matching of a point
+type MatchedPosition struct {
+	// Index is the index of the point in the session
+	Index int
+	Point MatchPoint
+	// Matched is false if the point could not be matched or its matching is not confident enough
+	Matched    bool
+	Location   geo.Point
+	Name       string
+	Confidence float64
+	// Gap is true if the trace is interrupted right before the position
+	Gap bool
+}
+
+type matchSessionPoint struct {
+	MatchPoint
+	hint string
+}
+
+// MatchSession matches an in-progress GPS trace incrementally.
+// Points are matched in sliding windows and the positions are emitted to the channel once
+// they are followed by enough points to be matched reliably.
+// MatchSession is not safe for concurrent use.
+type MatchSession struct {
+	osrm      *OSRM
+	cfg       MatchSessionConfig
+	points    []matchSessionPoint
+	emitted   int // number of points at the start of the window which are already emitted
+	offset    int // session index of the first point of the window
+	positions chan MatchedPosition
+}
+
+// NewMatchSession creates a map matching session
+func (o *OSRM) NewMatchSession(cfg MatchSessionConfig) *MatchSession {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = defaultMatchSessionWindowSize
+	}
+	if cfg.Overlap <= 0 {
+		cfg.Overlap = defaultMatchSessionOverlap
+	}
+	if cfg.WindowSize <= 2*cfg.Overlap {
+		cfg.Overlap = (cfg.WindowSize - 1) / 2
+	}
+	if cfg.Gaps == "" {
+		cfg.Gaps = GapsSplit
+	}
+
+	return &MatchSession{
+		osrm:      o,
+		cfg:       cfg,
+		positions: make(chan MatchedPosition, cfg.Buffer),
+	}
+}
+
+// Positions returns the channel of confirmed positions, it is closed by Close
+func (s *MatchSession) Positions() <-chan MatchedPosition {
+	return s.positions
+}
+
+// Add appends points to the trace and matches the window once it is full.
+// Confirmed positions are sent to the positions channel, thus it blocks until they are read or the context is done.
+// Points are kept on failure, so Add could be retried without points.
+func (s *MatchSession) Add(ctx context.Context, points ...MatchPoint) error {
+	for _, p := range points {
+		s.points = append(s.points, matchSessionPoint{MatchPoint: p})
+	}
+	for len(s.points) >= s.cfg.WindowSize {
+		if err := s.match(ctx, s.cfg.WindowSize-s.cfg.Overlap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush matches and emits all the remaining points
+func (s *MatchSession) Flush(ctx context.Context) error {
+	// more points than a window are kept if Add failed, they are matched window by window the same way
+	for len(s.points) > s.cfg.WindowSize {
+		if err := s.match(ctx, s.cfg.WindowSize-s.cfg.Overlap); err != nil {
+			return err
+		}
+	}
+	if len(s.points) == s.emitted {
+		return nil
+	}
+	return s.match(ctx, len(s.points))
+}
+
+// Close flushes the remaining points and closes the positions channel
+func (s *MatchSession) Close(ctx context.Context) error {
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+	close(s.positions)
+	return nil
+}
+
+// match matches the window and emits its points up to the confirmed index
+func (s *MatchSession) match(ctx context.Context, confirmed int) error {
+	window := s.points
+	if len(window) > s.cfg.WindowSize {
+		window = window[:s.cfg.WindowSize]
+	}
+
+	tracepoints, matchings, err := s.query(ctx, window)
+	if err != nil {
+		return err
+	}
+
+	for i := s.emitted; i < confirmed; i++ {
+		p := MatchedPosition{
+			Index: s.offset + i,
+			Point: window[i].MatchPoint,
+		}
+		if tp := tracepoints[i]; tp != nil && tp.MatchingIndex < len(matchings) {
+			p.Confidence = matchings[tp.MatchingIndex].Confidence
+			p.Matched = p.Confidence >= s.cfg.MinConfidence
+		}
+		if p.Matched {
+			p.Location = tracepoints[i].Location
+			p.Name = tracepoints[i].Name
+		}
+		if i > 0 {
+			prev, cur := tracepoints[i-1], tracepoints[i]
+			p.Gap = prev == nil || cur == nil || prev.MatchingIndex != cur.MatchingIndex
+		}
+
+		select {
+		case s.positions <- p:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.emitted = i + 1
+	}
+
+	// keep hints to speed up matching of the points in the next window
+	for i, tp := range tracepoints {
+		if tp != nil {
+			s.points[i].hint = tp.Hint
+		}
+	}
+
+	// keep the overlap of confirmed points as a context for the next window
+	if drop := confirmed - s.cfg.Overlap; drop > 0 {
+		s.points = append(s.points[:0:0], s.points[drop:]...)
+		s.offset += drop
+		s.emitted -= drop
+	}
+	return nil
+}
+
+func (s *MatchSession) query(ctx context.Context, window []matchSessionPoint) ([]*Tracepoint, []Matching, error) {
+	req := MatchRequest{
+		Profile:     s.cfg.Profile,
+		Coordinates: NewGeometryFromPointSet(make(geo.PointSet, len(window))),
+		Tidy:        s.cfg.Tidy,
+		Gaps:        s.cfg.Gaps,
+		Overview:    OverviewFalse,
+	}
+
+	withTimestamps, withBearings, withHints := true, false, false
+	for _, p := range window {
+		withTimestamps = withTimestamps && p.Timestamp > 0
+		withBearings = withBearings || p.Bearing != nil
+		withHints = withHints || p.hint != ""
+	}
+	for i, p := range window {
+		req.Coordinates.PointSet[i] = p.Location
+		if withHints {
+			req.Hints = append(req.Hints, p.hint)
+		}
+		radius := p.Radius
+		if radius <= 0 {
+			radius = defaultMatchSessionRadius
+		}
+		req.Radiuses = append(req.Radiuses, radius)
+		if withTimestamps {
+			req.Timestamps = append(req.Timestamps, p.Timestamp)
+		}
+		if withBearings {
+			bearing := Bearing{0, 180} // any direction
+			if p.Bearing != nil {
+				bearing = *p.Bearing
+			}
+			req.Bearings = append(req.Bearings, bearing)
+		}
+	}
+
+	resp, err := s.osrm.Match(ctx, req)
+	var status ResponseStatus
+	if errors.As(err, &status) && status.ErrCode() == ErrorCodeNoMatch {
+		// none of the points could be matched
+		return make([]*Tracepoint, len(window)), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp.Tracepoints) != len(window) {
+		return nil, nil, fmt.Errorf("osrm5: match response has %d tracepoints for %d points", len(resp.Tracepoints), len(window))
+	}
+	return resp.Tracepoints, resp.Matchings, nil
+}
@@ -0,0 +1,176 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matchSessionHandler matches every point to itself, points with zero latitude are unmatched,
+// points with negative longitude belong to a low confidence matching
+func matchSessionHandler(t *testing.T, queries *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+		encoded := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/match/v1/car/polyline("), ")")
+		path := geo.NewPathFromEncoding(encoded, polyline5Factor)
+
+		resp := MatchResponse{
+			ResponseStatus: ResponseStatus{Code: errorCodeOK},
+			Matchings:      []Matching{{Confidence: 0.9}, {Confidence: 0.1}},
+		}
+		for _, p := range path.PointSet {
+			if p.Lat() == 0 {
+				resp.Tracepoints = append(resp.Tracepoints, nil)
+				continue
+			}
+			tp := &Tracepoint{
+				Location: p,
+				Name:     fmt.Sprintf("street %g", p.Lat()),
+				Hint:     fmt.Sprintf("h%g", p.Lat()),
+			}
+			if p.Lng() < 0 {
+				tp.MatchingIndex = 1
+			}
+			resp.Tracepoints = append(resp.Tracepoints, tp)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}
+}
+
+func TestMatchSession(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(matchSessionHandler(t, &queries))
+	defer ts.Close()
+
+	session := NewFromURL(ts.URL).NewMatchSession(MatchSessionConfig{
+		Profile:       "car",
+		WindowSize:    5,
+		Overlap:       1,
+		MinConfidence: 0.5,
+		Buffer:        100,
+	})
+
+	var points []MatchPoint
+	for i := 1; i <= 12; i++ {
+		p := MatchPoint{Location: geo.Point{1, float64(i)}, Timestamp: int64(i)}
+		switch i {
+		case 6:
+			p.Location[1] = 0
+		case 9:
+			p.Location[0] = -1
+		}
+		points = append(points, p)
+	}
+
+	ctx := context.Background()
+	require.NoError(t, session.Add(ctx, points[:4]...))
+	assert.Empty(t, queries)
+	require.NoError(t, session.Add(ctx, points[4:]...))
+	assert.Len(t, queries, 3)
+	require.NoError(t, session.Close(ctx))
+	assert.Len(t, queries, 4)
+
+	// the first window has no hints, next ones reuse hints of the overlap
+	assert.NotContains(t, queries[0], "hints")
+	assert.Contains(t, queries[1], "hints=h4;h5;;;")
+	assert.Contains(t, queries[0], "timestamps=1;2;3;4;5")
+	assert.Contains(t, queries[0], "radiuses=5;5;5;5;5")
+	assert.Contains(t, queries[0], "gaps=split")
+
+	var positions []MatchedPosition
+	for p := range session.Positions() {
+		positions = append(positions, p)
+	}
+	require.Len(t, positions, 12)
+	for i, p := range positions {
+		assert.Equal(t, i, p.Index)
+		assert.Equal(t, points[i], p.Point)
+		switch i + 1 {
+		case 6, 9:
+			assert.False(t, p.Matched, "point %d", i+1)
+			assert.True(t, p.Gap, "point %d", i+1)
+		case 7, 10:
+			assert.True(t, p.Matched, "point %d", i+1)
+			assert.True(t, p.Gap, "point %d", i+1)
+		default:
+			assert.True(t, p.Matched, "point %d", i+1)
+			assert.False(t, p.Gap, "point %d", i+1)
+			assert.Equal(t, fmt.Sprintf("street %d", i+1), p.Name)
+			assert.Equal(t, points[i].Location, p.Location)
+		}
+	}
+}
+
+func TestMatchSessionNoMatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, `{"code": "NoMatch", "message": "Could not match the trace."}`)
+	}))
+	defer ts.Close()
+
+	session := NewFromURL(ts.URL).NewMatchSession(MatchSessionConfig{Profile: "car", Buffer: 2})
+	ctx := context.Background()
+	require.NoError(t, session.Add(ctx, MatchPoint{Location: geo.Point{1, 1}}, MatchPoint{Location: geo.Point{1, 2}}))
+	require.NoError(t, session.Close(ctx))
+
+	var positions []MatchedPosition
+	for p := range session.Positions() {
+		positions = append(positions, p)
+	}
+	require.Len(t, positions, 2)
+	assert.False(t, positions[0].Matched)
+	assert.False(t, positions[1].Matched)
+}
+
+func TestMatchSessionError(t *testing.T) {
+	ts := httptest.NewServer(fixturedHTTPHandler("invalid_query_response", func(path, query string) {}))
+	defer ts.Close()
+
+	session := NewFromURL(ts.URL).NewMatchSession(MatchSessionConfig{Profile: "car", WindowSize: 2})
+	err := session.Add(context.Background(), MatchPoint{Location: geo.Point{1, 1}}, MatchPoint{Location: geo.Point{1, 2}})
+	require.EqualError(t, err, "InvalidQuery - Query string malformed close to position 28")
+}
+
+func TestMatchSessionCloseAfterFailedAdd(t *testing.T) {
+	var queries []string
+	failing := true
+	handler := matchSessionHandler(t, &queries)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r)
+	}))
+	defer ts.Close()
+
+	session := NewFromURL(ts.URL).NewMatchSession(MatchSessionConfig{Profile: "car", WindowSize: 5, Overlap: 1, Buffer: 100})
+	var points []MatchPoint
+	for i := 1; i <= 12; i++ {
+		points = append(points, MatchPoint{Location: geo.Point{1, float64(i)}})
+	}
+
+	ctx := context.Background()
+	require.Error(t, session.Add(ctx, points...))
+	failing = false
+	require.NoError(t, session.Close(ctx))
+	assert.Len(t, queries, 4)
+
+	var positions []MatchedPosition
+	for p := range session.Positions() {
+		positions = append(positions, p)
+	}
+	require.Len(t, positions, 12)
+	for i, p := range positions {
+		assert.Equal(t, i, p.Index)
+		assert.True(t, p.Matched, "point %d", i+1)
+	}
+}
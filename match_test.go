@@ -50,6 +50,13 @@ func TestEmptyMatchRequestOptions(t *testing.T) {
 			},
 			expectedURI: "bearings=0%2C20%3B10%2C20&geometries=polyline6",
 		},
+		{
+			name: "with multiple annotations",
+			request: MatchRequest{
+				Annotations: NewAnnotations(AnnotationsNodes, AnnotationsDatasources),
+			},
+			expectedURI: "annotations=nodes%2Cdatasources&geometries=polyline6",
+		},
 		{
 			name: "custom overview option",
 			request: MatchRequest{
@@ -0,0 +1,268 @@
+package osrm
+
+import (
+	"context"
+	"math"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+const (
+	defaultNavigationMaxOffset           = 50.0 // meters
+	defaultNavigationMaxBearingDeviation = 90.0 // degrees
+	defaultNavigationOffRouteFixes       = 3
+	defaultNavigationOnRouteFixes        = 2
+	defaultNavigationArrivalDistance     = 30.0 // meters
+	navigationRerouteBearingRange        = 45
+)
+
+// NavigationEventType represents a kind of navigation event
+type NavigationEventType string
+
+// Navigation event types
+const (
+	// NavigationOnRoute is emitted for every fix following the route
+	NavigationOnRoute NavigationEventType = "on route"
+	// NavigationOffRoute is emitted once the driver leaves the route
+	NavigationOffRoute NavigationEventType = "off route"
+	// NavigationRerouted is emitted once a new route is built from the driver position
+	NavigationRerouted NavigationEventType = "rerouted"
+	// NavigationArrived is emitted once the driver reaches the last destination
+	NavigationArrived NavigationEventType = "arrived"
+)
+
+// NavigationConfig represents options of a navigation session
+type NavigationConfig struct {
+	// Profile is OSRM profile used for rerouting.
+	Profile string
+	// Steps and Annotations are requested for new routes.
+	Steps       Steps
+	Annotations Annotations
+	// MaxOffset is the distance in meters between a fix and the route above which the fix is off the route.
+	// Accuracy of the fix is added to it. 50 meters will be used as default if not set.
+	MaxOffset float64
+	// MaxBearingDeviation is the difference in degrees between the fix bearing and the route direction
+	// above which the fix is off the route. 90 degrees will be used as default if not set.
+	MaxBearingDeviation float64
+	// OffRouteFixes is the number of consecutive off-route fixes which makes the driver off the route.
+	// 3 fixes will be used as default if not set.
+	OffRouteFixes int
+	// OnRouteFixes is the number of consecutive on-route fixes which brings the driver back to the route.
+	// 2 fixes will be used as default if not set.
+	OnRouteFixes int
+	// ArrivalDistance is the remaining distance in meters at which the driver is considered arrived.
+	// 30 meters will be used as default if not set.
+	ArrivalDistance float64
+	// Buffer is the size of events channel buffer.
+	Buffer int
+}
+
+// NavigationFix represents a GPS fix of the driver
+type NavigationFix struct {
+	Location geo.Point
+	// Bearing is optional heading of the vehicle in degrees towards true north in clockwise direction.
+	Bearing *float64
+	// Accuracy is the GPS accuracy in meters.
+	Accuracy float64
+}
+
+// NavigationEvent represents a change of the navigation state
+type NavigationEvent struct {
+	Type NavigationEventType
+	Fix  NavigationFix
+	// Progress is the fix projected onto the active route
+	Progress RouteProgress
+	// Route is the new route for NavigationRerouted events
+	Route *Route
+}
+
+// Navigator follows the driver along the active route, detects when the driver is off the route
+// and builds a new route from the driver position to the remaining destinations.
+// Navigator is not safe for concurrent use.
+type Navigator struct {
+	osrm         *OSRM
+	cfg          NavigationConfig
+	route        Route
+	destinations geo.PointSet
+	offRoute     bool
+	arrived      bool
+	offFixes     int
+	onFixes      int
+	events       chan NavigationEvent
+}
+
+// NewNavigator creates a navigation session along the route.
+// Destinations are the locations the route legs end at, so there should be a destination per leg.
+// Fixes are projected onto the overview geometry, thus ErrNoOverviewGeometry is returned if the route has none.
+func (o *OSRM) NewNavigator(cfg NavigationConfig, route Route, destinations geo.PointSet) (*Navigator, error) {
+	if len(route.Geometry.PointSet) < 2 {
+		return nil, ErrNoOverviewGeometry
+	}
+	if cfg.MaxOffset <= 0 {
+		cfg.MaxOffset = defaultNavigationMaxOffset
+	}
+	if cfg.MaxBearingDeviation <= 0 {
+		cfg.MaxBearingDeviation = defaultNavigationMaxBearingDeviation
+	}
+	if cfg.OffRouteFixes <= 0 {
+		cfg.OffRouteFixes = defaultNavigationOffRouteFixes
+	}
+	if cfg.OnRouteFixes <= 0 {
+		cfg.OnRouteFixes = defaultNavigationOnRouteFixes
+	}
+	if cfg.ArrivalDistance <= 0 {
+		cfg.ArrivalDistance = defaultNavigationArrivalDistance
+	}
+
+	return &Navigator{
+		osrm:         o,
+		cfg:          cfg,
+		route:        route,
+		destinations: destinations,
+		events:       make(chan NavigationEvent, cfg.Buffer),
+	}, nil
+}
+
+// Events returns the channel of navigation events, it is closed by Close
+func (n *Navigator) Events() <-chan NavigationEvent {
+	return n.events
+}
+
+// Route returns the active route
+func (n *Navigator) Route() Route {
+	return n.route
+}
+
+// Close closes the events channel
+func (n *Navigator) Close() {
+	close(n.events)
+}
+
+// Update processes the fix and emits the events it causes.
+// Events are sent to the events channel, thus it blocks until they are read or the context is done.
+// The driver stays off the route if rerouting fails, so it is retried with the next fix.
+// Fixes are ignored after arrival.
+func (n *Navigator) Update(ctx context.Context, fix NavigationFix) error {
+	if n.arrived {
+		return nil
+	}
+
+	progress, ok := n.route.Progress(fix.Location)
+	onRoute := ok && n.follows(fix, progress)
+
+	if onRoute {
+		n.offFixes = 0
+		n.onFixes++
+		if n.offRoute && n.onFixes < n.cfg.OnRouteFixes {
+			return nil
+		}
+		n.offRoute = false
+		if progress.RemainingDistance <= n.cfg.ArrivalDistance {
+			n.arrived = true
+			return n.emit(ctx, NavigationEvent{Type: NavigationArrived, Fix: fix, Progress: progress})
+		}
+		return n.emit(ctx, NavigationEvent{Type: NavigationOnRoute, Fix: fix, Progress: progress})
+	}
+
+	n.onFixes = 0
+	n.offFixes++
+	if n.offFixes < n.cfg.OffRouteFixes {
+		return nil
+	}
+	if !n.offRoute {
+		n.offRoute = true
+		if err := n.emit(ctx, NavigationEvent{Type: NavigationOffRoute, Fix: fix, Progress: progress}); err != nil {
+			return err
+		}
+	}
+	return n.reroute(ctx, fix, progress)
+}
+
+// follows returns true if the fix is close enough to the route and heads in the route direction
+func (n *Navigator) follows(fix NavigationFix, progress RouteProgress) bool {
+	if progress.Offset > n.cfg.MaxOffset+fix.Accuracy {
+		return false
+	}
+	ps := n.route.Geometry.PointSet
+	if fix.Bearing == nil || progress.Segment+1 >= len(ps) {
+		return true
+	}
+	direction := ps[progress.Segment].BearingTo(&ps[progress.Segment+1])
+	return bearingDeviation(*fix.Bearing, direction) <= n.cfg.MaxBearingDeviation
+}
+
+func (n *Navigator) reroute(ctx context.Context, fix NavigationFix, progress RouteProgress) error {
+	remaining := n.remainingDestinations(progress)
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	req := RouteRequest{
+		Profile:     n.cfg.Profile,
+		Coordinates: NewGeometryFromPointSet(append(geo.PointSet{fix.Location}, remaining...)),
+		Steps:       n.cfg.Steps,
+		Annotations: n.cfg.Annotations,
+		Overview:    OverviewFull,
+	}
+	if fix.Bearing != nil {
+		req.Bearings = make([]Bearing, len(req.Coordinates.PointSet))
+		req.Bearings[0] = Bearing{Value: uint16(math.Mod(math.Round(*fix.Bearing)+360, 360)), Range: navigationRerouteBearingRange}
+		for i := 1; i < len(req.Bearings); i++ {
+			req.Bearings[i] = Bearing{0, 180} // any direction
+		}
+	}
+
+	resp, err := n.osrm.Route(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Routes) == 0 {
+		return nil
+	}
+	if len(resp.Routes[0].Geometry.PointSet) < 2 {
+		return ErrNoOverviewGeometry
+	}
+
+	route := resp.Routes[0]
+	n.route = route
+	n.destinations = remaining
+	n.offRoute = false
+	n.offFixes, n.onFixes = 0, 0
+
+	progress, _ = n.route.Progress(fix.Location)
+	// the event gets its own copy, so later reroutes don't change it
+	return n.emit(ctx, NavigationEvent{Type: NavigationRerouted, Fix: fix, Progress: progress, Route: &route})
+}
+
+// remainingDestinations returns the destinations of legs which end further than the progress
+func (n *Navigator) remainingDestinations(progress RouteProgress) geo.PointSet {
+	if len(n.destinations) != len(n.route.Legs) {
+		return n.destinations
+	}
+	var legEnd float64
+	for i, l := range n.route.Legs {
+		legEnd += float64(l.Distance)
+		if legEnd > progress.Distance {
+			return n.destinations[i:]
+		}
+	}
+	return n.destinations[len(n.destinations)-1:]
+}
+
+func (n *Navigator) emit(ctx context.Context, e NavigationEvent) error {
+	select {
+	case n.events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bearingDeviation returns the absolute difference of bearings in degrees
+func bearingDeviation(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
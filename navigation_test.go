@@ -0,0 +1,170 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func navigationEvents(n *Navigator) []NavigationEventType {
+	var types []NavigationEventType
+	for {
+		select {
+		case e := <-n.Events():
+			types = append(types, e.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestNavigator(t *testing.T) {
+	detour := Route{
+		Distance: 360,
+		Duration: 40,
+		Geometry: NewGeometryFromPointSet(geo.PointSet{
+			{13.392, 52.516},
+			{13.392, 52.518},
+			{13.390, 52.518},
+		}),
+		Legs: []RouteLeg{{Distance: 360, Duration: 40}},
+	}
+
+	var paths, queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		require.NoError(t, json.NewEncoder(w).Encode(RouteResponse{
+			ResponseStatus: ResponseStatus{Code: errorCodeOK},
+			Routes:         []Route{detour},
+			Waypoints: []Waypoint{
+				{Location: geo.Point{13.392, 52.516}},
+				{Location: geo.Point{13.390, 52.518}},
+			},
+		}))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	n, err := NewFromURL(ts.URL).NewNavigator(NavigationConfig{Profile: "car", Buffer: 10}, progressRoute(), geo.PointSet{{13.390, 52.518}})
+	require.NoError(t, err)
+	defer n.Close()
+
+	north, south := 0.0, 180.0
+	fixes := []NavigationFix{
+		{Location: geo.Point{13.388, 52.5172}, Bearing: &north},
+		// a single fix heading the wrong way doesn't make the driver off the route
+		{Location: geo.Point{13.388, 52.5174}, Bearing: &south},
+		{Location: geo.Point{13.388, 52.5176}, Bearing: &north},
+	}
+	for _, f := range fixes {
+		require.NoError(t, n.Update(ctx, f))
+	}
+	assert.Equal(t, []NavigationEventType{NavigationOnRoute, NavigationOnRoute}, navigationEvents(n))
+	assert.Empty(t, paths)
+
+	off := NavigationFix{Location: geo.Point{13.392, 52.516}, Bearing: &north, Accuracy: 10}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, n.Update(ctx, off))
+	}
+	assert.Equal(t, []NavigationEventType{NavigationOffRoute, NavigationRerouted}, navigationEvents(n))
+	require.Len(t, paths, 1)
+	rerouted := NewGeometryFromPointSet(geo.PointSet{{13.392, 52.516}, {13.390, 52.518}})
+	assert.Equal(t, "/route/v1/car/polyline("+rerouted.Polyline()+")", paths[0])
+	assert.Equal(t, "bearings=0%2C45%3B0%2C180&geometries=polyline6&overview=full", queries[0])
+	assert.Equal(t, detour.Geometry.PointSet, n.Route().Geometry.PointSet)
+
+	require.NoError(t, n.Update(ctx, NavigationFix{Location: geo.Point{13.392, 52.517}}))
+	require.NoError(t, n.Update(ctx, NavigationFix{Location: geo.Point{13.3902, 52.5181}}))
+	// fixes after arrival are ignored
+	require.NoError(t, n.Update(ctx, NavigationFix{Location: geo.Point{13.3902, 52.5181}}))
+	assert.Equal(t, []NavigationEventType{NavigationOnRoute, NavigationArrived}, navigationEvents(n))
+}
+
+func TestNavigatorReroutes(t *testing.T) {
+	detour := Route{
+		Geometry: NewGeometryFromPointSet(geo.PointSet{
+			{13.392, 52.516},
+			{13.392, 52.518},
+			{13.390, 52.518},
+		}),
+	}
+
+	var distance float32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		distance += 1000
+		route := detour
+		route.Distance = distance
+		route.Legs = []RouteLeg{{Distance: distance}}
+		require.NoError(t, json.NewEncoder(w).Encode(RouteResponse{
+			ResponseStatus: ResponseStatus{Code: errorCodeOK},
+			Routes:         []Route{route},
+		}))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	n, err := NewFromURL(ts.URL).NewNavigator(NavigationConfig{Profile: "car", Buffer: 10, OffRouteFixes: 1}, progressRoute(), geo.PointSet{{13.390, 52.518}})
+	require.NoError(t, err)
+	defer n.Close()
+
+	// off the initial route and then off the detour
+	require.NoError(t, n.Update(ctx, NavigationFix{Location: geo.Point{13.392, 52.516}}))
+	require.NoError(t, n.Update(ctx, NavigationFix{Location: geo.Point{13.388, 52.5172}}))
+
+	var routes []*Route
+	for len(n.Events()) > 0 {
+		if e := <-n.Events(); e.Type == NavigationRerouted {
+			routes = append(routes, e.Route)
+		}
+	}
+	require.Len(t, routes, 2)
+	assert.Equal(t, float32(1000), routes[0].Distance)
+	assert.Equal(t, float32(2000), routes[1].Distance)
+	assert.Equal(t, float32(2000), n.Route().Distance)
+}
+
+func TestNavigatorWithoutOverview(t *testing.T) {
+	route := progressRoute()
+	route.Geometry = Geometry{}
+	_, err := NewFromURL("http://127.0.0.1:0").NewNavigator(NavigationConfig{}, route, geo.PointSet{{13.390, 52.518}})
+	assert.Equal(t, ErrNoOverviewGeometry, err)
+}
+
+func TestNavigatorHysteresis(t *testing.T) {
+	n, err := NewFromURL("http://127.0.0.1:0").NewNavigator(NavigationConfig{Buffer: 10, OffRouteFixes: 2, OnRouteFixes: 2}, progressRoute(), geo.PointSet{{13.390, 52.518}})
+	require.NoError(t, err)
+	defer n.Close()
+
+	ctx := context.Background()
+	on := NavigationFix{Location: geo.Point{13.388, 52.5172}}
+	off := NavigationFix{Location: geo.Point{13.392, 52.516}}
+
+	require.NoError(t, n.Update(ctx, off))
+	require.NoError(t, n.Update(ctx, on))
+	require.NoError(t, n.Update(ctx, off))
+	assert.Equal(t, []NavigationEventType{NavigationOnRoute}, navigationEvents(n))
+
+	// rerouting fails, so the driver stays off the route
+	assert.Error(t, n.Update(ctx, off))
+	assert.Equal(t, []NavigationEventType{NavigationOffRoute}, navigationEvents(n))
+	assert.Error(t, n.Update(ctx, off))
+	assert.Empty(t, navigationEvents(n))
+
+	require.NoError(t, n.Update(ctx, on))
+	assert.Empty(t, navigationEvents(n))
+	require.NoError(t, n.Update(ctx, on))
+	assert.Equal(t, []NavigationEventType{NavigationOnRoute}, navigationEvents(n))
+}
+
+func TestBearingDeviation(t *testing.T) {
+	assert.Equal(t, float64(20), bearingDeviation(350, 10))
+	assert.Equal(t, float64(20), bearingDeviation(10, -10))
+	assert.Equal(t, float64(180), bearingDeviation(90, -90))
+}
@@ -8,6 +8,7 @@ type NearestRequest struct {
 	Coordinates Geometry
 	Bearings    []Bearing
 	Number      int
+	Format      Format
 }
 
 // NearestResponse represents a response from the nearest method
@@ -38,6 +39,7 @@ func (r NearestRequest) request() *request {
 	return &request{
 		profile: r.Profile,
 		service: "nearest",
+		format:  r.Format,
 		coords:  r.Coordinates,
 		options: opts,
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"time"
+
+	geo "github.com/paulmach/go.geo"
 )
 
 const (
@@ -18,6 +20,7 @@ const (
 // TODO: implement (trip, tile) methods
 type OSRM struct {
 	client
+	probes map[string]geo.Point
 }
 
 // Config represents OSRM client configuration options
@@ -28,6 +31,20 @@ type Config struct {
 	// Client is custom pre-configured http client to be used for queries.
 	// New http.Client instance with default settings and one second timeout will be used if not set.
 	Client HTTPClient
+	// Headers are added to every request sent to OSRM server, e.g. User-Agent or tenant headers.
+	Headers http.Header
+	// RequestMutator is called for every request right before it is sent to OSRM server.
+	RequestMutator RequestMutator
+	// OnDataVersionChange is called when a backend starts responding with a new data_version,
+	// e.g. to correlate ETA shifts with map updates. Versions are tracked per backend the responses are received from.
+	// It is called synchronously from the query which has noticed the change, changes are delivered one by one in order,
+	// thus it must not make queries with the same client.
+	OnDataVersionChange func(DataVersionChange)
+	// Caches are invalidated before OnDataVersionChange is called when a backend changes its data_version.
+	Caches []DataVersionCache
+	// HealthProbes maps profiles to coordinates queried with the nearest service by HealthCheck.
+	// Car profile probed at (0, 0) will be used as default if not set.
+	HealthProbes map[string]geo.Point
 }
 
 // ResponseStatus represent OSRM API response
@@ -46,6 +63,10 @@ func (r ResponseStatus) Error() string {
 	return r.Code + " - " + r.Message
 }
 
+func (r ResponseStatus) dataVersion() string {
+	return r.DataVersion
+}
+
 func (r ResponseStatus) apiError() error {
 	if r.Code != errorCodeOK {
 		return r
@@ -55,6 +76,12 @@ func (r ResponseStatus) apiError() error {
 
 type response interface {
 	apiError() error
+	dataVersion() string
+}
+
+// geometriesResponse is a response containing geometries encoded according to the request
+type geometriesResponse interface {
+	DecodeGeometries(requested Geometries) error
 }
 
 // New creates a client with default server url and default timeout
@@ -84,14 +111,39 @@ func NewWithConfig(cfg Config) *OSRM {
 		cfg.Client = &http.Client{Timeout: defaultTimeout}
 	}
 
-	return &OSRM{client: newClient(cfg.ServerURL, cfg.Client)}
+	c := newClient(cfg.ServerURL, cfg.Client)
+	c.headers = cfg.Headers
+	c.mutator = cfg.RequestMutator
+	c.versions = newDataVersionTracker(cfg.OnDataVersionChange, cfg.Caches)
+
+	return &OSRM{
+		client: c,
+		probes: cfg.HealthProbes,
+	}
 }
 
 func (o OSRM) query(ctx context.Context, in *request, out response) error {
 	if err := o.client.doRequest(ctx, in, out); err != nil {
 		return err
 	}
-	return out.apiError()
+	if err := out.apiError(); err != nil {
+		return err
+	}
+	if g, ok := out.(geometriesResponse); ok {
+		return g.DecodeGeometries(in.geometries)
+	}
+	return nil
+}
+
+// DataVersion returns the last data_version seen in OSRM responses of any backend.
+// It is empty until the first successful query.
+func (o OSRM) DataVersion() string {
+	return o.versions.version()
+}
+
+// DataVersions returns the last data_version seen in responses of every backend by its scheme and host
+func (o OSRM) DataVersions() map[string]string {
+	return o.versions.byBackend()
 }
 
 // Route searches the shortest path between given coordinates.
@@ -153,7 +153,7 @@ func TestRouteRequest(t *testing.T) {
 	require.Len(leg0.Steps, 7)
 	// routes/legs/steps[0]
 	step0 := leg0.Steps[0]
-	require.Equal("driving", step0.Mode)
+	require.Equal(TravelModeDriving, step0.Mode)
 	require.Equal("", step0.Name)
 	require.Equal(float32(5.0), step0.Duration)
 	require.Equal(float32(33.1), step0.Distance)
@@ -0,0 +1,217 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// PooledStop represents a planned stop of a pooled vehicle
+type PooledStop struct {
+	Location geo.Point
+	// Rider identifies the rider picked up or dropped off at the stop
+	Rider int
+	// Pickup is true for pickups and false for dropoffs.
+	// Riders having only the dropoff in the plan are on board.
+	Pickup bool
+}
+
+// InsertionRequest represents a new ride to be inserted into the plan of a pooled vehicle
+type InsertionRequest struct {
+	// Profile is OSRM profile used for the table request.
+	Profile string
+	// MaxTableSize is the maximal number of coordinates in a table request.
+	// OSRM default of 100 coordinates will be used if not set.
+	MaxTableSize int
+	// Vehicle is the current location of the vehicle
+	Vehicle geo.Point
+	// Stops are planned stops in the order of visiting
+	Stops []PooledStop
+	// Rider identifies the new rider going from Pickup to Dropoff
+	Rider           int
+	Pickup, Dropoff geo.Point
+	// Capacity is the maximal number of riders on board, there is no limit if not set
+	Capacity int
+	// MaxDetour is the maximal ratio of a ride duration to the planned one, the new rider's ride is compared
+	// to the direct trip. There is no limit if not set.
+	MaxDetour float64
+	// MaxAddedDuration is the maximal increase of the plan duration, there is no limit if not set
+	MaxAddedDuration time.Duration
+}
+
+// RiderDetour represents the change of a ride duration caused by an insertion.
+// Rides of riders on board are measured from the current location of the vehicle.
+type RiderDetour struct {
+	Rider int
+	// Duration and PlannedDuration in seconds are ride durations with and without the insertion
+	Duration, PlannedDuration float64
+	// Ratio is Duration to PlannedDuration ratio
+	Ratio float64
+}
+
+// Insertion represents the plan of a pooled vehicle with a new ride inserted
+type Insertion struct {
+	// PickupIndex and DropoffIndex are indices of the new stops in Stops
+	PickupIndex, DropoffIndex int
+	Stops                     []PooledStop
+	// AddedDuration in seconds and AddedDistance in meters are increases of the plan duration and distance
+	AddedDuration, AddedDistance float64
+	// Detours are ride changes of riders in the plan and the new rider, ordered by the dropoff
+	Detours []RiderDetour
+}
+
+// Insertions evaluates all feasible positions of the new ride in the plan with a single table request
+// unless the plan exceeds MaxTableSize, the cheapest insertion by the added duration goes first
+func (o OSRM) Insertions(ctx context.Context, req InsertionRequest) ([]Insertion, error) {
+	// points are the vehicle, planned stops, the pickup and the dropoff
+	points := geo.PointSet{req.Vehicle}
+	for _, s := range req.Stops {
+		points = append(points, s.Location)
+	}
+	points = append(points, req.Pickup, req.Dropoff)
+
+	durations, distances, err := o.squareMatrices(ctx, req.Profile, req.MaxTableSize, points, true)
+	if err != nil {
+		return nil, err
+	}
+
+	pickup, dropoff := len(points)-2, len(points)-1
+	planned := make([]int, len(req.Stops))
+	for i := range planned {
+		planned[i] = i + 1
+	}
+	plannedPlan := evaluatePooledPlan(req, planned, durations, distances)
+
+	var insertions []Insertion
+	for i := 0; i <= len(planned); i++ {
+		for j := i; j <= len(planned); j++ {
+			sequence := make([]int, 0, len(planned)+2)
+			sequence = append(sequence, planned[:i]...)
+			sequence = append(sequence, pickup)
+			sequence = append(sequence, planned[i:j]...)
+			sequence = append(sequence, dropoff)
+			sequence = append(sequence, planned[j:]...)
+
+			plan := evaluatePooledPlan(req, sequence, durations, distances)
+			if !plan.feasible {
+				continue
+			}
+
+			ins := Insertion{
+				PickupIndex:   i,
+				DropoffIndex:  j + 1,
+				AddedDuration: plan.duration - plannedPlan.duration,
+				AddedDistance: plan.distance - plannedPlan.distance,
+			}
+			if req.MaxAddedDuration > 0 && ins.AddedDuration > req.MaxAddedDuration.Seconds() {
+				continue
+			}
+
+			ok := true
+			for _, rider := range plan.dropoffOrder {
+				d := RiderDetour{Rider: rider, Duration: plan.rides[rider], PlannedDuration: plannedPlan.rides[rider]}
+				if rider == req.Rider {
+					d.PlannedDuration = durations[pickup][dropoff]
+				}
+				d.Ratio = 1
+				if d.PlannedDuration > 0 {
+					d.Ratio = d.Duration / d.PlannedDuration
+				}
+				ok = ok && (req.MaxDetour <= 0 || d.Ratio <= req.MaxDetour)
+				ins.Detours = append(ins.Detours, d)
+			}
+			if !ok {
+				continue
+			}
+
+			for _, k := range sequence {
+				switch k {
+				case pickup:
+					ins.Stops = append(ins.Stops, PooledStop{Location: req.Pickup, Rider: req.Rider, Pickup: true})
+				case dropoff:
+					ins.Stops = append(ins.Stops, PooledStop{Location: req.Dropoff, Rider: req.Rider})
+				default:
+					ins.Stops = append(ins.Stops, req.Stops[k-1])
+				}
+			}
+			insertions = append(insertions, ins)
+		}
+	}
+
+	sort.SliceStable(insertions, func(i, j int) bool { return insertions[i].AddedDuration < insertions[j].AddedDuration })
+	return insertions, nil
+}
+
+// BestInsertion returns the feasible insertion of the new ride adding the least duration to the plan
+func (o OSRM) BestInsertion(ctx context.Context, req InsertionRequest) (*Insertion, error) {
+	insertions, err := o.Insertions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(insertions) == 0 {
+		return nil, ErrNoFeasibleInsertion
+	}
+	return &insertions[0], nil
+}
+
+type pooledPlan struct {
+	feasible           bool
+	duration, distance float64
+	rides              map[int]float64 // ride durations by rider
+	dropoffOrder       []int
+}
+
+// evaluatePooledPlan drives the vehicle along the sequence of point indices,
+// the new ride is the pair of the last two points
+func evaluatePooledPlan(req InsertionRequest, sequence []int, durations, distances [][]float64) pooledPlan {
+	pickup, dropoff := len(durations)-2, len(durations)-1
+	stop := func(k int) PooledStop {
+		switch k {
+		case pickup:
+			return PooledStop{Rider: req.Rider, Pickup: true}
+		case dropoff:
+			return PooledStop{Rider: req.Rider}
+		default:
+			return req.Stops[k-1]
+		}
+	}
+
+	plan := pooledPlan{feasible: true, rides: make(map[int]float64)}
+	pickedUp := make(map[int]float64)
+	// riders on board have only dropoffs in the plan
+	var load int
+	for _, s := range req.Stops {
+		if s.Pickup {
+			load--
+		} else {
+			load++
+		}
+	}
+
+	from := 0
+	for _, k := range sequence {
+		plan.duration += durations[from][k]
+		plan.distance += distances[from][k]
+		from = k
+
+		s := stop(k)
+		if s.Pickup {
+			pickedUp[s.Rider] = plan.duration
+			load++
+		} else {
+			plan.rides[s.Rider] = plan.duration - pickedUp[s.Rider]
+			plan.dropoffOrder = append(plan.dropoffOrder, s.Rider)
+			load--
+		}
+		if req.Capacity > 0 && load > req.Capacity {
+			plan.feasible = false
+		}
+	}
+	if math.IsInf(plan.duration, 1) {
+		plan.feasible = false
+	}
+	return plan
+}
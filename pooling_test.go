@@ -0,0 +1,104 @@
+package osrm
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertionOnTheWay(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	ps := dispatchLocations(0, 1, 3, 4)
+	req := InsertionRequest{
+		Profile: "car",
+		Vehicle: ps[0],
+		Stops:   []PooledStop{{Location: ps[3], Rider: 1}},
+		Rider:   2,
+		Pickup:  ps[1],
+		Dropoff: ps[2],
+	}
+	ins, err := NewFromURL(ts.URL).BestInsertion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// the plan exceeding the table size is split into several requests
+	req.MaxTableSize = 3
+	split, err := NewFromURL(ts.URL).BestInsertion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 9, requests)
+	assert.Equal(t, ins, split)
+
+	assert.Equal(t, 0, ins.PickupIndex)
+	assert.Equal(t, 1, ins.DropoffIndex)
+	assert.Equal(t, []PooledStop{
+		{Location: ps[1], Rider: 2, Pickup: true},
+		{Location: ps[2], Rider: 2},
+		{Location: ps[3], Rider: 1},
+	}, ins.Stops)
+	assert.InDelta(t, 0, ins.AddedDuration, 1e-6)
+	assert.InDelta(t, 0, ins.AddedDistance, 1e-3)
+
+	require.Len(t, ins.Detours, 2)
+	assert.Equal(t, 2, ins.Detours[0].Rider)
+	assert.InDelta(t, 1, ins.Detours[0].Ratio, 1e-6)
+	assert.InDelta(t, 222, ins.Detours[0].Duration, 1)
+	assert.Equal(t, 1, ins.Detours[1].Rider)
+	assert.InDelta(t, 1, ins.Detours[1].Ratio, 1e-6)
+}
+
+func TestInsertionConstraints(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	ps := dispatchLocations(0, 1, 3, -1)
+	req := InsertionRequest{
+		Profile: "car",
+		Vehicle: ps[0],
+		Stops:   []PooledStop{{Location: ps[1], Rider: 1}},
+		Rider:   2,
+		Pickup:  ps[2],
+		Dropoff: ps[3],
+	}
+	osrm := NewFromURL(ts.URL)
+
+	insertions, err := osrm.Insertions(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, insertions, 3)
+	var detoured *Insertion
+	for i, ins := range insertions {
+		if ins.PickupIndex == 0 && ins.DropoffIndex == 2 {
+			detoured = &insertions[i]
+		}
+	}
+	require.NotNil(t, detoured)
+	assert.Equal(t, 1, detoured.Detours[0].Rider)
+	assert.InDelta(t, 5, detoured.Detours[0].Ratio, 1e-3)
+
+	// the rider on board is dropped off first to avoid the detour
+	req.MaxDetour = 1.5
+	ins, err := osrm.BestInsertion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ins.PickupIndex)
+	assert.Equal(t, 2, ins.DropoffIndex)
+	assert.InDelta(t, 667, ins.AddedDuration, 1)
+	assert.InDelta(t, 6679, ins.AddedDistance, 5)
+
+	req.MaxDetour = 0
+	req.Capacity = 1
+	insertions, err = osrm.Insertions(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, insertions, 1)
+	assert.Equal(t, 1, insertions[0].PickupIndex)
+
+	req.MaxAddedDuration = 5 * time.Minute
+	_, err = osrm.BestInsertion(context.Background(), req)
+	assert.Equal(t, ErrNoFeasibleInsertion, err)
+}
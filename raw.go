@@ -0,0 +1,35 @@
+package osrm
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RawResponse contains details of HTTP exchange with OSRM server.
+// Attach it to a context with WithRawResponse to capture them for a query.
+type RawResponse struct {
+	// URL is the exact URL sent to OSRM server
+	URL string
+	// StatusCode is HTTP status code of the response
+	StatusCode int
+	// Header contains HTTP headers of the response
+	Header http.Header
+	// Body is the response body as it was returned by OSRM server
+	Body []byte
+	// Latency is time spent from sending the request until the body is read
+	Latency time.Duration
+}
+
+type rawResponseKey struct{}
+
+// WithRawResponse returns a copy of the context which makes a query fill in the given raw response.
+// The raw response is filled in even if the query fails, URL is set even if the request isn't sent or fails in transport.
+func WithRawResponse(ctx context.Context, raw *RawResponse) context.Context {
+	return context.WithValue(ctx, rawResponseKey{}, raw)
+}
+
+func rawResponseFromContext(ctx context.Context) *RawResponse {
+	raw, _ := ctx.Value(rawResponseKey{}).(*RawResponse)
+	return raw
+}
@@ -0,0 +1,308 @@
+package osrm
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+const defaultMaxTableSize = 100 // OSRM default limit of coordinates in a table request
+
+// ReachabilityConfig represents options of reachability queries
+type ReachabilityConfig struct {
+	// Profile is OSRM profile used for table requests.
+	Profile string
+	// MaxTableSize is the maximal number of coordinates in a table request.
+	// OSRM default of 100 coordinates will be used if not set.
+	MaxTableSize int
+}
+
+// DriverReach represents the duration a driver needs to reach a location
+type DriverReach struct {
+	// Index is the index of the driver in the input
+	Index    int
+	Location geo.Point
+	// Duration in seconds
+	Duration float64
+}
+
+// DriversReaching returns the drivers which can reach the pickup within the deadline, the fastest driver goes first
+func (o OSRM) DriversReaching(ctx context.Context, cfg ReachabilityConfig, drivers geo.PointSet, pickup geo.Point, deadline time.Duration) ([]DriverReach, error) {
+	durations, _, err := o.tableMatrices(ctx, cfg.Profile, cfg.MaxTableSize, drivers, geo.PointSet{pickup}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var reaching []DriverReach
+	for i, d := range durations {
+		if d[0] <= deadline.Seconds() {
+			reaching = append(reaching, DriverReach{Index: i, Location: drivers[i], Duration: d[0]})
+		}
+	}
+	sort.SliceStable(reaching, func(i, j int) bool { return reaching[i].Duration < reaching[j].Duration })
+	return reaching, nil
+}
+
+// HexCell represents a cell of a hexagonal grid in axial coordinates
+type HexCell struct {
+	Q, R int
+}
+
+// HexGrid represents a grid of pointy-top hexagons around the origin.
+// Hexagons are laid out on the equirectangular projection at the origin latitude,
+// which is accurate enough for city-sized areas.
+type HexGrid struct {
+	Origin geo.Point
+	// Size is the distance in meters from the center of a hexagon to its corners
+	Size float64
+}
+
+// NewHexGrid creates a hexagonal grid around the origin
+func NewHexGrid(origin geo.Point, size float64) HexGrid {
+	return HexGrid{Origin: origin, Size: size}
+}
+
+// Cell returns the cell containing the point
+func (g HexGrid) Cell(p geo.Point) HexCell {
+	x, y := g.project(p)
+	q := (math.Sqrt(3)/3*x - y/3) / g.Size
+	r := 2.0 / 3 * y / g.Size
+
+	// round cube coordinates keeping their sum zero
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	if dq > dr && dq > ds {
+		rq = -rr - rs
+	} else if dr > ds {
+		rr = -rq - rs
+	}
+	return HexCell{Q: int(rq), R: int(rr)}
+}
+
+// Center returns the center of the cell
+func (g HexGrid) Center(c HexCell) geo.Point {
+	x := g.Size * math.Sqrt(3) * (float64(c.Q) + float64(c.R)/2)
+	y := g.Size * 1.5 * float64(c.R)
+	return g.unproject(x, y)
+}
+
+// Boundary returns the corners of the cell
+func (g HexGrid) Boundary(c HexCell) geo.PointSet {
+	center := g.Center(c)
+	cx, cy := g.project(center)
+	corners := make(geo.PointSet, 6)
+	for i := range corners {
+		angle := math.Pi / 180 * float64(60*i-30)
+		corners[i] = g.unproject(cx+g.Size*math.Cos(angle), cy+g.Size*math.Sin(angle))
+	}
+	return corners
+}
+
+// CellsWithin returns the cells which centers are within the distance in meters from the origin cell center
+func (g HexGrid) CellsWithin(distance float64) []HexCell {
+	// centers of the k-th ring are at least 1.5 sizes per ring away
+	k := int(distance / (1.5 * g.Size))
+	var cells []HexCell
+	for q := -k; q <= k; q++ {
+		for r := -k; r <= k; r++ {
+			if s := -q - r; s < -k || s > k {
+				continue
+			}
+			x := g.Size * math.Sqrt(3) * (float64(q) + float64(r)/2)
+			y := g.Size * 1.5 * float64(r)
+			if math.Hypot(x, y) <= distance {
+				cells = append(cells, HexCell{Q: q, R: r})
+			}
+		}
+	}
+	return cells
+}
+
+func (g HexGrid) project(p geo.Point) (x, y float64) {
+	return (p.Lng() - g.Origin.Lng()) * metersPerDegree * math.Cos(g.Origin.Lat()*math.Pi/180),
+		(p.Lat() - g.Origin.Lat()) * metersPerDegree
+}
+
+func (g HexGrid) unproject(x, y float64) geo.Point {
+	return geo.Point{
+		g.Origin.Lng() + x/(metersPerDegree*math.Cos(g.Origin.Lat()*math.Pi/180)),
+		g.Origin.Lat() + y/metersPerDegree,
+	}
+}
+
+// CellCoverage represents the number of drivers reaching a cell
+type CellCoverage struct {
+	Cell    HexCell
+	Center  geo.Point
+	Drivers int
+}
+
+// Coverage represents the number of drivers reaching cells of a hexagonal grid within a duration
+type Coverage struct {
+	Grid   HexGrid
+	Within time.Duration
+	Cells  []CellCoverage
+}
+
+// Coverage counts drivers reaching centers of the cells within the duration
+func (o OSRM) Coverage(ctx context.Context, cfg ReachabilityConfig, drivers geo.PointSet, grid HexGrid, cells []HexCell, within time.Duration) (*Coverage, error) {
+	centers := make(geo.PointSet, len(cells))
+	for i, c := range cells {
+		centers[i] = grid.Center(c)
+	}
+
+	durations, _, err := o.tableMatrices(ctx, cfg.Profile, cfg.MaxTableSize, drivers, centers, false)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &Coverage{Grid: grid, Within: within, Cells: make([]CellCoverage, len(cells))}
+	for j, c := range cells {
+		coverage.Cells[j] = CellCoverage{Cell: c, Center: centers[j]}
+		for i := range drivers {
+			if durations[i][j] <= within.Seconds() {
+				coverage.Cells[j].Drivers++
+			}
+		}
+	}
+	return coverage, nil
+}
+
+// ToGeoJSON exports the coverage as hexagon polygons with "q", "r" and "drivers" properties
+func (c Coverage) ToGeoJSON() *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for _, cell := range c.Cells {
+		boundary := c.Grid.Boundary(cell.Cell)
+		ring := make([][]float64, 0, len(boundary)+1)
+		for _, p := range append(boundary, boundary[0]) {
+			ring = append(ring, []float64{p.Lng(), p.Lat()})
+		}
+		f := geojson.NewPolygonFeature([][][]float64{ring})
+		f.SetProperty("q", cell.Cell.Q)
+		f.SetProperty("r", cell.Cell.R)
+		f.SetProperty("drivers", cell.Drivers)
+		fc.AddFeature(f)
+	}
+	return fc
+}
+
+// tableMatrices returns durations in seconds and optionally distances in meters from every source
+// to every destination splitting them into table requests of at most maxSize coordinates.
+// Unreachable pairs have infinite durations and distances.
+func (o OSRM) tableMatrices(ctx context.Context, profile string, maxSize int, sources, destinations geo.PointSet, withDistances bool) (durations, distances [][]float64, err error) {
+	if maxSize < 2 {
+		maxSize = defaultMaxTableSize
+	}
+	sourcesBlock := len(sources)
+	if sourcesBlock > maxSize/2 {
+		sourcesBlock = maxSize / 2
+	}
+	destinationsBlock := maxSize - sourcesBlock
+
+	durations = newMatrix(len(sources), len(destinations))
+	if withDistances {
+		distances = newMatrix(len(sources), len(destinations))
+	}
+
+	var annotations Annotations
+	if withDistances {
+		annotations = NewAnnotations(AnnotationsDuration, AnnotationsDistance)
+	}
+
+	for si := 0; si < len(sources); si += sourcesBlock {
+		src := sources[si:]
+		if len(src) > sourcesBlock {
+			src = src[:sourcesBlock]
+		}
+		for di := 0; di < len(destinations); di += destinationsBlock {
+			dst := destinations[di:]
+			if len(dst) > destinationsBlock {
+				dst = dst[:destinationsBlock]
+			}
+
+			req := TableRequest{
+				Profile:     profile,
+				Coordinates: NewGeometryFromPointSet(append(append(geo.PointSet{}, src...), dst...)),
+				Annotations: annotations,
+			}
+			for i := range src {
+				req.Sources = append(req.Sources, i)
+			}
+			for j := range dst {
+				req.Destinations = append(req.Destinations, len(src)+j)
+			}
+
+			resp, err := o.Table(ctx, req)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range src {
+				for j := range dst {
+					durations[si+i][di+j] = matrixValue(resp.Durations, i, j)
+					if withDistances {
+						distances[si+i][di+j] = matrixValue(resp.Distances, i, j)
+					}
+				}
+			}
+		}
+	}
+	return durations, distances, nil
+}
+
+// squareMatrices returns durations in seconds and optionally distances in meters between all the points.
+// Points fitting into maxSize are sent once in a single table request, otherwise they are split by tableMatrices.
+func (o OSRM) squareMatrices(ctx context.Context, profile string, maxSize int, points geo.PointSet, withDistances bool) (durations, distances [][]float64, err error) {
+	if maxSize < 2 {
+		maxSize = defaultMaxTableSize
+	}
+	if len(points) > maxSize {
+		return o.tableMatrices(ctx, profile, maxSize, points, points, withDistances)
+	}
+
+	req := TableRequest{
+		Profile:     profile,
+		Coordinates: NewGeometryFromPointSet(points),
+	}
+	if withDistances {
+		req.Annotations = NewAnnotations(AnnotationsDuration, AnnotationsDistance)
+	}
+	resp, err := o.Table(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	durations = newMatrix(len(points), len(points))
+	if withDistances {
+		distances = newMatrix(len(points), len(points))
+	}
+	for i := range points {
+		for j := range points {
+			durations[i][j] = matrixValue(resp.Durations, i, j)
+			if withDistances {
+				distances[i][j] = matrixValue(resp.Distances, i, j)
+			}
+		}
+	}
+	return durations, distances, nil
+}
+
+func newMatrix(rows, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+// matrixValue returns the table value, missing values are infinite
+func matrixValue(m [][]float32, i, j int) float64 {
+	if i < len(m) && j < len(m[i]) {
+		return float64(m[i][j])
+	}
+	return math.Inf(1)
+}
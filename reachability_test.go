@@ -0,0 +1,193 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matrixHandler responds to table requests with distances and durations of driving straight at 10 m/s,
+// points to the east of the unreachable longitude can't be reached
+func matrixHandler(t *testing.T, requests *int, unreachable float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		encoded := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/table/v1/car/polyline("), ")")
+		ps := geo.NewPathFromEncoding(encoded, polyline5Factor).PointSet
+
+		// net/url doesn't accept semicolons separating OSRM option values
+		query := make(map[string]string)
+		for _, kv := range strings.Split(r.URL.RawQuery, "&") {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) == 2 {
+				query[parts[0]] = parts[1]
+			}
+		}
+		// all coordinates are sources and destinations if not set
+		indices := func(name string) []int {
+			var idx []int
+			if query[name] == "" {
+				for i := range ps {
+					idx = append(idx, i)
+				}
+				return idx
+			}
+			for _, s := range strings.Split(query[name], ";") {
+				i, err := strconv.Atoi(s)
+				require.NoError(t, err)
+				idx = append(idx, i)
+			}
+			return idx
+		}
+		sources, destinations := indices("sources"), indices("destinations")
+		if query["sources"] != "" && query["destinations"] != "" {
+			assert.True(t, len(sources)+len(destinations) <= len(ps))
+		}
+
+		durations := make([][]*float64, len(sources))
+		distances := make([][]*float64, len(sources))
+		for i, s := range sources {
+			durations[i] = make([]*float64, len(destinations))
+			distances[i] = make([]*float64, len(destinations))
+			for j, d := range destinations {
+				if ps[s].Lng() > unreachable || ps[d].Lng() > unreachable {
+					continue
+				}
+				distance := ps[s].GeoDistanceFrom(&ps[d], true)
+				duration := distance / 10
+				durations[i][j], distances[i][j] = &duration, &distance
+			}
+		}
+		resp := map[string]interface{}{
+			"code":      "Ok",
+			"durations": durations,
+		}
+		if strings.Contains(query["annotations"], "distance") {
+			resp["distances"] = distances
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}
+}
+
+func TestDriversReaching(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 13.5))
+	defer ts.Close()
+
+	pickup := geo.Point{13.388860, 52.517037}
+	drivers := geo.PointSet{
+		{13.388860, 52.547037}, // ~3.3 km
+		{13.388860, 52.527037}, // ~1.1 km
+		{13.388860, 52.617037}, // ~11 km
+		{13.6, 52.517037},      // unreachable
+	}
+
+	reaching, err := NewFromURL(ts.URL).DriversReaching(context.Background(), ReachabilityConfig{Profile: "car"}, drivers, pickup, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	require.Len(t, reaching, 2)
+	assert.Equal(t, 1, reaching[0].Index)
+	assert.Equal(t, drivers[1], reaching[0].Location)
+	assert.InDelta(t, 111, reaching[0].Duration, 1)
+	assert.Equal(t, 0, reaching[1].Index)
+	assert.InDelta(t, 334, reaching[1].Duration, 1)
+}
+
+func TestDriversReachingInBatches(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	pickup := geo.Point{13.388860, 52.517037}
+	var drivers geo.PointSet
+	for i := 0; i < 25; i++ {
+		drivers = append(drivers, geo.Point{13.388860, 52.517037 + float64(i)*0.001})
+	}
+
+	reaching, err := NewFromURL(ts.URL).DriversReaching(context.Background(), ReachabilityConfig{Profile: "car", MaxTableSize: 10}, drivers, pickup, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 5, requests)
+	require.Len(t, reaching, 25)
+	for i, r := range reaching {
+		assert.Equal(t, i, r.Index)
+	}
+}
+
+func TestHexGrid(t *testing.T) {
+	grid := NewHexGrid(geo.Point{13.388860, 52.517037}, 500)
+
+	assert.Equal(t, HexCell{}, grid.Cell(grid.Origin))
+	for _, c := range grid.CellsWithin(3000) {
+		center := grid.Center(c)
+		assert.Equal(t, c, grid.Cell(center))
+
+		boundary := grid.Boundary(c)
+		require.Len(t, boundary, 6)
+		for _, p := range boundary {
+			assert.InDelta(t, 500, center.GeoDistanceFrom(&p, true), 5)
+		}
+	}
+
+	// neighbors are sqrt(3) sizes apart
+	center, east := grid.Center(HexCell{}), grid.Center(HexCell{Q: 1})
+	assert.InDelta(t, 866, center.GeoDistanceFrom(&east, true), 5)
+
+	assert.Len(t, grid.CellsWithin(100), 1)
+	assert.Len(t, grid.CellsWithin(900), 7)
+	assert.Len(t, grid.CellsWithin(1800), 19)
+
+	// the second ring has centers at 1500 and 1732 meters
+	cells := grid.CellsWithin(1600)
+	assert.Len(t, cells, 13)
+	for _, c := range cells {
+		center := grid.Center(c)
+		assert.True(t, grid.Origin.GeoDistanceFrom(&center, true) <= 1600+5, "cell %v", c)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(matrixHandler(t, &requests, 180))
+	defer ts.Close()
+
+	grid := NewHexGrid(geo.Point{13.388860, 52.517037}, 500)
+	cells := grid.CellsWithin(1800)
+	drivers := geo.PointSet{
+		grid.Center(HexCell{}),
+		grid.Center(HexCell{Q: 1}),
+		grid.Center(HexCell{Q: 2}),
+	}
+
+	coverage, err := NewFromURL(ts.URL).Coverage(context.Background(), ReachabilityConfig{Profile: "car"}, drivers, grid, cells, 90*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	require.Len(t, coverage.Cells, len(cells))
+
+	counts := make(map[HexCell]int)
+	for _, c := range coverage.Cells {
+		counts[c.Cell] = c.Drivers
+	}
+	// drivers reach their own and neighbor cells within 90 seconds at 10 m/s
+	assert.Equal(t, 2, counts[HexCell{}])
+	assert.Equal(t, 3, counts[HexCell{Q: 1}])
+	assert.Equal(t, 2, counts[HexCell{Q: 2}])
+	assert.Equal(t, 1, counts[HexCell{Q: -1}])
+	assert.Equal(t, 0, counts[HexCell{Q: -2}])
+
+	fc := coverage.ToGeoJSON()
+	require.Len(t, fc.Features, len(cells))
+	ring := fc.Features[0].Geometry.Polygon[0]
+	assert.Len(t, ring, 7)
+	assert.Equal(t, ring[0], ring[6])
+	bytes, err := fc.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(bytes), `"drivers":`)
+}
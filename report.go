@@ -0,0 +1,188 @@
+package osrm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Intersection classes of OSRM car profile
+const (
+	ClassMotorway   = "motorway"
+	ClassToll       = "toll"
+	ClassFerry      = "ferry"
+	ClassTunnel     = "tunnel"
+	ClassRestricted = "restricted"
+)
+
+// turnManeuvers are maneuvers counted as turns unless they go straight
+var turnManeuvers = map[ManeuverType]bool{
+	ManeuverTypeTurn:           true,
+	ManeuverTypeContinue:       true,
+	ManeuverTypeEndOfRoad:      true,
+	ManeuverTypeFork:           true,
+	ManeuverTypeOnRamp:         true,
+	ManeuverTypeOffRamp:        true,
+	ManeuverTypeRamp:           true,
+	ManeuverTypeMerge:          true,
+	ManeuverTypeRoundabout:     true,
+	ManeuverTypeRotary:         true,
+	ManeuverTypeRoundaboutTurn: true,
+}
+
+// RoadUsage represents the part of a trip driven along a road
+type RoadUsage struct {
+	// Name is the name of the road, or its ref if the road has no name
+	Name string
+	// Distance in meters and Duration in seconds driven along the road
+	Distance, Duration float64
+	// Share is the share of the trip distance driven along the road
+	Share float64
+}
+
+// LegReport represents structured summary of route steps
+type LegReport struct {
+	// Distance in meters and Duration in seconds of the steps
+	Distance, Duration float64
+	// Roads are named roads sorted by the distance driven along them, the longest goes first
+	Roads []RoadUsage
+	// Turns is the number of maneuvers changing the direction, departure and arrival are not counted
+	Turns int
+	// HighwayDistance and HighwayDuration are driven along motorways,
+	// LocalDistance and LocalDuration along other roads
+	HighwayDistance, HighwayDuration float64
+	LocalDistance, LocalDuration     float64
+	// ClassDistances are distances in meters driven along roads of intersection classes, e.g. toll or ferry
+	ClassDistances map[string]float64
+}
+
+// Report aggregates steps of the leg, the leg should be requested with steps
+func (l RouteLeg) Report() LegReport {
+	var b reportBuilder
+	b.addSteps(l.Steps)
+	return b.report()
+}
+
+// LegReports returns reports of every leg of the route
+func (r Route) LegReports() []LegReport {
+	reports := make([]LegReport, len(r.Legs))
+	for i, l := range r.Legs {
+		reports[i] = l.Report()
+	}
+	return reports
+}
+
+// Report aggregates steps of all the legs of the route
+func (r Route) Report() LegReport {
+	var b reportBuilder
+	for _, l := range r.Legs {
+		b.addSteps(l.Steps)
+	}
+	return b.report()
+}
+
+// MajorRoads returns at most n longest roads
+func (r LegReport) MajorRoads(n int) []RoadUsage {
+	if len(r.Roads) < n {
+		return r.Roads
+	}
+	return r.Roads[:n]
+}
+
+// HighwayTimeShare returns the share of the duration driven along motorways
+func (r LegReport) HighwayTimeShare() float64 {
+	if r.Duration == 0 {
+		return 0
+	}
+	return r.HighwayDuration / r.Duration
+}
+
+// String returns a human summary, e.g. "3.2 km, 8 min via Broadway (55%), 5th Avenue (30%); 4 turns, 0% highway time"
+func (r LegReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%.1f km, %.0f min", r.Distance/1000, r.Duration/60)
+	for i, road := range r.MajorRoads(3) {
+		if i == 0 {
+			sb.WriteString(" via ")
+		} else {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s (%.0f%%)", road.Name, road.Share*100)
+	}
+	fmt.Fprintf(&sb, "; %d turns, %.0f%% highway time", r.Turns, r.HighwayTimeShare()*100)
+	return sb.String()
+}
+
+type reportBuilder struct {
+	LegReport
+	roads map[string]*RoadUsage
+}
+
+func (b *reportBuilder) addSteps(steps []RouteStep) {
+	if b.roads == nil {
+		b.roads = make(map[string]*RoadUsage)
+		b.ClassDistances = make(map[string]float64)
+	}
+
+	for _, s := range steps {
+		distance, duration := float64(s.Distance), float64(s.Duration)
+		b.Distance += distance
+		b.Duration += duration
+
+		if turnManeuvers[s.Maneuver.Type] && s.Maneuver.Modifier != ManeuverModifierStraight {
+			b.Turns++
+		}
+
+		if name := roadName(s); name != "" {
+			road, ok := b.roads[name]
+			if !ok {
+				road = &RoadUsage{Name: name}
+				b.roads[name] = road
+			}
+			road.Distance += distance
+			road.Duration += duration
+		}
+
+		// classes of the first intersection describe the road the step goes along
+		var classes []string
+		if len(s.Intersections) > 0 {
+			classes = s.Intersections[0].Classes
+		}
+		highway := false
+		for _, c := range classes {
+			b.ClassDistances[c] += distance
+			highway = highway || c == ClassMotorway
+		}
+		if highway {
+			b.HighwayDistance += distance
+			b.HighwayDuration += duration
+		} else {
+			b.LocalDistance += distance
+			b.LocalDuration += duration
+		}
+	}
+}
+
+func (b *reportBuilder) report() LegReport {
+	r := b.LegReport
+	for _, road := range b.roads {
+		if r.Distance > 0 {
+			road.Share = road.Distance / r.Distance
+		}
+		r.Roads = append(r.Roads, *road)
+	}
+	sort.Slice(r.Roads, func(i, j int) bool {
+		if r.Roads[i].Distance != r.Roads[j].Distance {
+			return r.Roads[i].Distance > r.Roads[j].Distance
+		}
+		return r.Roads[i].Name < r.Roads[j].Name
+	})
+	return r
+}
+
+func roadName(s RouteStep) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return strings.Split(s.Ref, ";")[0]
+}
@@ -0,0 +1,64 @@
+package osrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteLegReport(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+
+	report := resp.Routes[0].Legs[0].Report()
+
+	assert.InDelta(t, 1506.5, report.Distance, 1e-3)
+	assert.InDelta(t, 122.7, report.Duration, 1e-3)
+	assert.Equal(t, 2, report.Turns)
+
+	require.Len(t, report.Roads, 3)
+	assert.Equal(t, "A 100", report.Roads[0].Name)
+	assert.InDelta(t, 709.2, report.Roads[0].Distance, 1e-3)
+	assert.InDelta(t, 709.2/1506.5, report.Roads[0].Share, 1e-6)
+	assert.Equal(t, "Torstraße", report.Roads[1].Name)
+	assert.Equal(t, "Friedrichstraße", report.Roads[2].Name)
+
+	assert.InDelta(t, 709.2, report.HighwayDistance, 1e-3)
+	assert.InDelta(t, 53.2, report.HighwayDuration, 1e-3)
+	assert.InDelta(t, 797.3, report.LocalDistance, 1e-3)
+	assert.InDelta(t, 69.5, report.LocalDuration, 1e-3)
+	assert.InDelta(t, 709.2, report.ClassDistances[ClassToll], 1e-3)
+
+	assert.Equal(t, "1.5 km, 2 min via A 100 (47%), Torstraße (41%), Friedrichstraße (12%); 2 turns, 43% highway time", report.String())
+}
+
+func TestRouteReport(t *testing.T) {
+	straight := RouteLeg{Steps: []RouteStep{
+		{Name: "Broadway", Distance: 300, Duration: 30, Maneuver: StepManeuver{Type: ManeuverTypeDepart}},
+		{Name: "Broadway", Distance: 200, Duration: 20, Maneuver: StepManeuver{Type: ManeuverTypeContinue, Modifier: ManeuverModifierStraight}},
+		{Name: "Broadway", Maneuver: StepManeuver{Type: ManeuverTypeArrive}},
+	}}
+	turning := RouteLeg{Steps: []RouteStep{
+		{Name: "Broadway", Distance: 100, Duration: 10, Maneuver: StepManeuver{Type: ManeuverTypeDepart}},
+		{Ref: "I 95;US 1", Distance: 400, Duration: 20, Maneuver: StepManeuver{Type: ManeuverTypeTurn, Modifier: ManeuverModifierLeft}},
+		{Maneuver: StepManeuver{Type: ManeuverTypeArrive}},
+	}}
+	r := Route{Legs: []RouteLeg{straight, turning}}
+
+	reports := r.LegReports()
+	require.Len(t, reports, 2)
+	assert.Equal(t, 0, reports[0].Turns)
+	assert.Equal(t, []RoadUsage{{Name: "Broadway", Distance: 500, Duration: 50, Share: 1}}, reports[0].Roads)
+	assert.Equal(t, 1, reports[1].Turns)
+
+	report := r.Report()
+	assert.Equal(t, float64(1000), report.Distance)
+	assert.Equal(t, 1, report.Turns)
+	assert.Equal(t, []RoadUsage{
+		{Name: "Broadway", Distance: 600, Duration: 60, Share: 0.6},
+		{Name: "I 95", Distance: 400, Duration: 20, Share: 0.4},
+	}, report.Roads)
+	assert.Len(t, report.MajorRoads(1), 1)
+	assert.Equal(t, float64(0), report.HighwayTimeShare())
+}
@@ -18,6 +18,8 @@ type RouteRequest struct {
 	Geometries       Geometries
 	ContinueStraight ContinueStraight
 	Waypoints        []int
+	Alternatives     Alternatives
+	Format           Format
 }
 
 // RouteResponse represents a response from the route method
@@ -27,6 +29,7 @@ type RouteResponse struct {
 	Waypoints []Waypoint `json:"waypoints"`
 }
 
+// Waypoint represents a snapped input coordinate
 type Waypoint struct {
 	Name     string    `json:"name"`
 	Location geo.Point `json:"location"`
@@ -54,53 +57,150 @@ type RouteLeg struct {
 	Steps      []RouteStep `json:"steps"`
 }
 
-// Annotation contains additional metadata for each coordinate along the route geometry
-type Annotation struct {
-	Duration []float32 `json:"duration,omitempty"`
-	Distance []float32 `json:"distance,omitempty"`
-	Nodes    []uint64  `json:"nodes,omitempty"`
-}
-
 // RouteStep represents a route geometry
 type RouteStep struct {
-	Distance      float32        `json:"distance"`
-	Duration      float32        `json:"duration"`
-	Geometry      Geometry       `json:"geometry"`
-	Name          string         `json:"name"`
-	Mode          string         `json:"mode"`
-	DrivingSide   string         `json:"driving_side"`
-	Weight        float32        `json:"weight"`
-	Maneuver      StepManeuver   `json:"maneuver"`
-	Intersections []Intersection `json:"intersections,omitempty"`
+	Distance            float32        `json:"distance"`
+	Duration            float32        `json:"duration"`
+	Geometry            Geometry       `json:"geometry"`
+	Name                string         `json:"name"`
+	Ref                 string         `json:"ref,omitempty"`
+	Pronunciation       string         `json:"pronunciation,omitempty"`
+	Destinations        string         `json:"destinations,omitempty"`
+	Exits               string         `json:"exits,omitempty"`
+	Mode                TravelMode     `json:"mode"`
+	DrivingSide         string         `json:"driving_side"`
+	Weight              float32        `json:"weight"`
+	Maneuver            StepManeuver   `json:"maneuver"`
+	Intersections       []Intersection `json:"intersections,omitempty"`
+	RotaryName          string         `json:"rotary_name,omitempty"`
+	RotaryPronunciation string         `json:"rotary_pronunciation,omitempty"`
 }
 
+// Intersection represents a cross-way the route passes by along a step
 type Intersection struct {
 	Location geo.Point `json:"location"`
 	Bearings []uint16  `json:"bearings"`
+	Classes  []string  `json:"classes,omitempty"`
 	Entry    []bool    `json:"entry"`
 	In       *uint32   `json:"in,omitempty"`
 	Out      *uint32   `json:"out,omitempty"`
 	Lanes    []Lane    `json:"lanes,omitempty"`
+	// TurnWeight and TurnDuration are penalties of the turn at the intersection,
+	// they are included in the step weight and duration.
+	TurnWeight   float32 `json:"turn_weight,omitempty"`
+	TurnDuration float32 `json:"turn_duration,omitempty"`
 }
 
+// Lane represents a turn lane at an intersection
 type Lane struct {
-	Indications []string `json:"indications"`
-	Valid       bool     `json:"valid"`
+	Indications []LaneIndication `json:"indications"`
+	Valid       bool             `json:"valid"`
 }
 
 // StepManeuver contains information about maneuver in step
 type StepManeuver struct {
-	Location      geo.Point `json:"location"`
-	BearingBefore float32   `json:"bearing_before"`
-	BearingAfter  float32   `json:"bearing_after"`
-	Type          string    `json:"type"`
-	Modifier      string    `json:"modifier,omitempty"`
-	Exit          *uint32   `json:"exit,omitempty"`
+	Location      geo.Point        `json:"location"`
+	BearingBefore float32          `json:"bearing_before"`
+	BearingAfter  float32          `json:"bearing_after"`
+	Type          ManeuverType     `json:"type"`
+	Modifier      ManeuverModifier `json:"modifier,omitempty"`
+	Exit          *uint32          `json:"exit,omitempty"`
+}
+
+// TravelMode represents a mode of transportation of a route step
+type TravelMode string
+
+// Travel modes of OSRM profiles
+const (
+	TravelModeDriving      TravelMode = "driving"
+	TravelModeFerry        TravelMode = "ferry"
+	TravelModeTrain        TravelMode = "train"
+	TravelModeCycling      TravelMode = "cycling"
+	TravelModePushingBike  TravelMode = "pushing bike"
+	TravelModeWalking      TravelMode = "walking"
+	TravelModeInaccessible TravelMode = "inaccessible"
+)
+
+// String returns TravelMode as a string
+func (m TravelMode) String() string {
+	return string(m)
+}
+
+// ManeuverType represents a type of maneuver
+type ManeuverType string
+
+// Maneuver types
+const (
+	ManeuverTypeTurn           ManeuverType = "turn"
+	ManeuverTypeNewName        ManeuverType = "new name"
+	ManeuverTypeDepart         ManeuverType = "depart"
+	ManeuverTypeArrive         ManeuverType = "arrive"
+	ManeuverTypeMerge          ManeuverType = "merge"
+	ManeuverTypeRamp           ManeuverType = "ramp" // deprecated by OSRM in favor of on/off ramp
+	ManeuverTypeOnRamp         ManeuverType = "on ramp"
+	ManeuverTypeOffRamp        ManeuverType = "off ramp"
+	ManeuverTypeFork           ManeuverType = "fork"
+	ManeuverTypeEndOfRoad      ManeuverType = "end of road"
+	ManeuverTypeUseLane        ManeuverType = "use lane" // deprecated by OSRM, lanes are available in intersections
+	ManeuverTypeContinue       ManeuverType = "continue"
+	ManeuverTypeRoundabout     ManeuverType = "roundabout"
+	ManeuverTypeRotary         ManeuverType = "rotary"
+	ManeuverTypeRoundaboutTurn ManeuverType = "roundabout turn"
+	ManeuverTypeNotification   ManeuverType = "notification"
+	ManeuverTypeExitRoundabout ManeuverType = "exit roundabout"
+	ManeuverTypeExitRotary     ManeuverType = "exit rotary"
+)
+
+// String returns ManeuverType as a string
+func (t ManeuverType) String() string {
+	return string(t)
+}
+
+// ManeuverModifier represents a direction change of maneuver
+type ManeuverModifier string
+
+// Maneuver modifiers
+const (
+	ManeuverModifierUTurn       ManeuverModifier = "uturn"
+	ManeuverModifierSharpRight  ManeuverModifier = "sharp right"
+	ManeuverModifierRight       ManeuverModifier = "right"
+	ManeuverModifierSlightRight ManeuverModifier = "slight right"
+	ManeuverModifierStraight    ManeuverModifier = "straight"
+	ManeuverModifierSlightLeft  ManeuverModifier = "slight left"
+	ManeuverModifierLeft        ManeuverModifier = "left"
+	ManeuverModifierSharpLeft   ManeuverModifier = "sharp left"
+)
+
+// String returns ManeuverModifier as a string
+func (m ManeuverModifier) String() string {
+	return string(m)
+}
+
+// LaneIndication represents a turn indication of a lane
+type LaneIndication string
+
+// Lane indications
+const (
+	LaneIndicationNone        LaneIndication = "none"
+	LaneIndicationUTurn       LaneIndication = "uturn"
+	LaneIndicationSharpRight  LaneIndication = "sharp right"
+	LaneIndicationRight       LaneIndication = "right"
+	LaneIndicationSlightRight LaneIndication = "slight right"
+	LaneIndicationStraight    LaneIndication = "straight"
+	LaneIndicationSlightLeft  LaneIndication = "slight left"
+	LaneIndicationLeft        LaneIndication = "left"
+	LaneIndicationSharpLeft   LaneIndication = "sharp left"
+)
+
+// String returns LaneIndication as a string
+func (i LaneIndication) String() string {
+	return string(i)
 }
 
 func (r RouteRequest) request() *request {
 	opts := stepsOptions(r.Steps, r.Annotations, r.Overview, r.Geometries).
-		setStringer("continue_straight", r.ContinueStraight)
+		setStringer("continue_straight", r.ContinueStraight).
+		setStringer("alternatives", r.Alternatives)
 
 	if len(r.Waypoints) > 0 {
 		waypoints := ""
@@ -118,10 +218,12 @@ func (r RouteRequest) request() *request {
 	}
 
 	return &request{
-		profile: r.Profile,
-		coords:  r.Coordinates,
-		service: "route",
-		options: opts,
+		profile:    r.Profile,
+		coords:     r.Coordinates,
+		service:    "route",
+		format:     r.Format,
+		geometries: r.Geometries,
+		options:    opts,
 	}
 }
 
@@ -0,0 +1,234 @@
+package osrm
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// RouteVertex represents a vertex of a route geometry with the distance and the time it's reached at
+type RouteVertex struct {
+	Location geo.Point
+	// Distance in meters and Duration in seconds from the start of the route
+	Distance, Duration float64
+}
+
+// RouteProgress represents a position projected onto a route
+type RouteProgress struct {
+	// Location is the nearest to the position point of the route geometry
+	Location geo.Point
+	// Segment is the index of the geometry segment the location lies on
+	Segment int
+	// Offset is the distance in meters between the position and the route
+	Offset float64
+	// Distance in meters and Duration in seconds passed from the start of the route
+	Distance, Duration float64
+	// RemainingDistance in meters and RemainingDuration in seconds to the end of the route
+	RemainingDistance, RemainingDuration float64
+}
+
+// routeTimeline is a list of route vertices with cumulative distances and durations
+type routeTimeline []RouteVertex
+
+// Vertices returns the vertices of the route geometry with cumulative distances and durations.
+// Annotation distances and durations are used if the route is requested with the full overview
+// and the annotations, otherwise the route duration is spread along the geometry proportionally to the distance.
+func (r Route) Vertices() []RouteVertex {
+	return r.timeline()
+}
+
+// ETAs returns estimated times of arrival to every vertex of the route geometry
+func (r Route) ETAs(departure time.Time) []time.Time {
+	return r.timeline().etas(departure)
+}
+
+// PositionAt returns the position on the route after the given number of seconds from the start.
+// It returns false if the route has no geometry.
+func (r Route) PositionAt(elapsed float64) (geo.Point, bool) {
+	return r.timeline().interpolate(elapsed, vertexDuration)
+}
+
+// PositionAtDistance returns the position on the route after the given distance in meters from the start.
+// It returns false if the route has no geometry.
+func (r Route) PositionAtDistance(distance float64) (geo.Point, bool) {
+	return r.timeline().interpolate(distance, vertexDistance)
+}
+
+// Progress projects the position onto the route and returns the passed and remaining distance and duration.
+// It returns false if the route has no geometry.
+func (r Route) Progress(position geo.Point) (RouteProgress, bool) {
+	return r.timeline().progress(position)
+}
+
+func (r Route) timeline() routeTimeline {
+	annotations := make([]Annotation, len(r.Legs))
+	for i, l := range r.Legs {
+		annotations[i] = l.Annotation
+	}
+	return newRouteTimeline(r.Geometry, annotations, float64(r.Duration))
+}
+
+// Geometry returns the geometry of the leg assembled from geometries of its steps
+func (l RouteLeg) Geometry() Geometry {
+	var ps geo.PointSet
+	for _, s := range l.Steps {
+		for i, p := range s.Geometry.PointSet {
+			if i == 0 && len(ps) > 0 && ps[len(ps)-1].Equals(&p) {
+				continue // steps share their boundary points
+			}
+			ps = append(ps, p)
+		}
+	}
+	return NewGeometryFromPointSet(ps)
+}
+
+// Vertices returns the vertices of the leg geometry with cumulative distances and durations,
+// the leg should be requested with steps
+func (l RouteLeg) Vertices() []RouteVertex {
+	return l.timeline()
+}
+
+// ETAs returns estimated times of arrival to every vertex of the leg geometry
+func (l RouteLeg) ETAs(departure time.Time) []time.Time {
+	return l.timeline().etas(departure)
+}
+
+// PositionAt returns the position on the leg after the given number of seconds from the start.
+// It returns false if the leg has no steps.
+func (l RouteLeg) PositionAt(elapsed float64) (geo.Point, bool) {
+	return l.timeline().interpolate(elapsed, vertexDuration)
+}
+
+// PositionAtDistance returns the position on the leg after the given distance in meters from the start.
+// It returns false if the leg has no steps.
+func (l RouteLeg) PositionAtDistance(distance float64) (geo.Point, bool) {
+	return l.timeline().interpolate(distance, vertexDistance)
+}
+
+// Progress projects the position onto the leg and returns the passed and remaining distance and duration.
+// It returns false if the leg has no steps.
+func (l RouteLeg) Progress(position geo.Point) (RouteProgress, bool) {
+	return l.timeline().progress(position)
+}
+
+func (l RouteLeg) timeline() routeTimeline {
+	return newRouteTimeline(l.Geometry(), []Annotation{l.Annotation}, float64(l.Duration))
+}
+
+func newRouteTimeline(g Geometry, annotations []Annotation, duration float64) routeTimeline {
+	ps := g.PointSet
+	if len(ps) == 0 {
+		return nil
+	}
+
+	var distances, durations []float32
+	for _, a := range annotations {
+		distances = append(distances, a.Distance...)
+		durations = append(durations, a.Duration...)
+	}
+	// annotations describe geometry segments only if the geometry is full
+	if len(distances) != len(ps)-1 {
+		distances = nil
+	}
+	if len(durations) != len(ps)-1 {
+		durations = nil
+	}
+
+	t := make(routeTimeline, len(ps))
+	t[0].Location = ps[0]
+	for i := 1; i < len(ps); i++ {
+		t[i].Location = ps[i]
+		d := ps[i-1].GeoDistanceFrom(&ps[i], true)
+		if distances != nil {
+			d = float64(distances[i-1])
+		}
+		t[i].Distance = t[i-1].Distance + d
+		if durations != nil {
+			t[i].Duration = t[i-1].Duration + float64(durations[i-1])
+		}
+	}
+
+	if durations == nil {
+		total := t[len(t)-1].Distance
+		for i := range t {
+			if total > 0 {
+				t[i].Duration = duration * t[i].Distance / total
+			}
+		}
+	}
+	return t
+}
+
+func vertexDistance(v RouteVertex) float64 { return v.Distance }
+func vertexDuration(v RouteVertex) float64 { return v.Duration }
+
+func (t routeTimeline) etas(departure time.Time) []time.Time {
+	etas := make([]time.Time, len(t))
+	for i, v := range t {
+		etas[i] = departure.Add(time.Duration(v.Duration * float64(time.Second)))
+	}
+	return etas
+}
+
+// interpolate returns the position at which the measure reaches the value
+func (t routeTimeline) interpolate(value float64, measure func(RouteVertex) float64) (geo.Point, bool) {
+	if len(t) == 0 {
+		return geo.Point{}, false
+	}
+
+	i := sort.Search(len(t), func(i int) bool { return measure(t[i]) >= value })
+	if i == 0 {
+		return t[0].Location, true
+	}
+	if i == len(t) {
+		return t[len(t)-1].Location, true
+	}
+
+	a, b := t[i-1], t[i]
+	fraction := (value - measure(a)) / (measure(b) - measure(a))
+	return *geo.NewLine(&a.Location, &b.Location).Interpolate(fraction), true
+}
+
+func (t routeTimeline) progress(position geo.Point) (RouteProgress, bool) {
+	if len(t) == 0 {
+		return RouteProgress{}, false
+	}
+
+	best := RouteProgress{Location: t[0].Location, Offset: position.GeoDistanceFrom(&t[0].Location, true)}
+	fraction := 0.0
+	for i := 1; i < len(t); i++ {
+		f := projectOnSegment(position, t[i-1].Location, t[i].Location)
+		p := *geo.NewLine(&t[i-1].Location, &t[i].Location).Interpolate(f)
+		if offset := position.GeoDistanceFrom(&p, true); offset < best.Offset {
+			best = RouteProgress{Location: p, Segment: i - 1, Offset: offset}
+			fraction = f
+		}
+	}
+
+	last := t[len(t)-1]
+	a := t[best.Segment]
+	b := a
+	if best.Segment+1 < len(t) {
+		b = t[best.Segment+1]
+	}
+	best.Distance = a.Distance + fraction*(b.Distance-a.Distance)
+	best.Duration = a.Duration + fraction*(b.Duration-a.Duration)
+	best.RemainingDistance = last.Distance - best.Distance
+	best.RemainingDuration = last.Duration - best.Duration
+	return best, true
+}
+
+// projectOnSegment returns the fraction of the segment ab at which the point p is projected,
+// longitudes are scaled to make the projection accurate on short distances
+func projectOnSegment(p, a, b geo.Point) float64 {
+	scale := math.Cos(a.Lat() * math.Pi / 180)
+	dx, dy := (b.Lng()-a.Lng())*scale, b.Lat()-a.Lat()
+	d := dx*dx + dy*dy
+	if d == 0 {
+		return 0
+	}
+	f := ((p.Lng()-a.Lng())*scale*dx + (p.Lat()-a.Lat())*dy) / d
+	return math.Max(0, math.Min(1, f))
+}
@@ -0,0 +1,126 @@
+package osrm
+
+import (
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func progressRoute() Route {
+	return Route{
+		Distance: 300,
+		Duration: 50,
+		Geometry: NewGeometryFromPointSet(geo.PointSet{
+			{13.388, 52.517},
+			{13.388, 52.518},
+			{13.390, 52.518},
+		}),
+		Legs: []RouteLeg{{
+			Annotation: Annotation{
+				Distance: []float32{100, 200},
+				Duration: []float32{10, 40},
+			},
+		}},
+	}
+}
+
+func TestRouteVertices(t *testing.T) {
+	r := progressRoute()
+
+	assert.Equal(t, []RouteVertex{
+		{Location: geo.Point{13.388, 52.517}, Distance: 0, Duration: 0},
+		{Location: geo.Point{13.388, 52.518}, Distance: 100, Duration: 10},
+		{Location: geo.Point{13.390, 52.518}, Distance: 300, Duration: 50},
+	}, r.Vertices())
+
+	departure := time.Date(2021, 3, 15, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{
+		departure,
+		departure.Add(10 * time.Second),
+		departure.Add(50 * time.Second),
+	}, r.ETAs(departure))
+}
+
+func TestRouteVerticesWithoutAnnotations(t *testing.T) {
+	r := progressRoute()
+	r.Legs = nil
+
+	vertices := r.Vertices()
+	require.Len(t, vertices, 3)
+	assert.InDelta(t, 111.3, vertices[1].Distance, 0.1)
+	assert.InDelta(t, 246.8, vertices[2].Distance, 0.1)
+	// the route duration is spread proportionally to the distance
+	assert.InDelta(t, 50*vertices[1].Distance/vertices[2].Distance, vertices[1].Duration, 1e-9)
+	assert.InDelta(t, 50, vertices[2].Duration, 1e-9)
+}
+
+func TestRoutePositionAt(t *testing.T) {
+	r := progressRoute()
+
+	cases := []struct {
+		elapsed  float64
+		expected geo.Point
+	}{
+		{-1, geo.Point{13.388, 52.517}},
+		{0, geo.Point{13.388, 52.517}},
+		{5, geo.Point{13.388, 52.5175}},
+		{10, geo.Point{13.388, 52.518}},
+		{30, geo.Point{13.389, 52.518}},
+		{60, geo.Point{13.390, 52.518}},
+	}
+	for _, c := range cases {
+		p, ok := r.PositionAt(c.elapsed)
+		require.True(t, ok)
+		assert.InDelta(t, c.expected.Lng(), p.Lng(), 1e-9, "elapsed %v", c.elapsed)
+		assert.InDelta(t, c.expected.Lat(), p.Lat(), 1e-9, "elapsed %v", c.elapsed)
+	}
+
+	p, ok := r.PositionAtDistance(250)
+	require.True(t, ok)
+	assert.InDelta(t, 13.3895, p.Lng(), 1e-9)
+
+	_, ok = Route{}.PositionAt(10)
+	assert.False(t, ok)
+}
+
+func TestRouteProgress(t *testing.T) {
+	r := progressRoute()
+
+	progress, ok := r.Progress(geo.Point{13.389, 52.5181})
+	require.True(t, ok)
+
+	assert.Equal(t, 1, progress.Segment)
+	assert.InDelta(t, 13.389, progress.Location.Lng(), 1e-6)
+	assert.InDelta(t, 52.518, progress.Location.Lat(), 1e-6)
+	assert.InDelta(t, 11.1, progress.Offset, 0.1)
+	assert.InDelta(t, 200, progress.Distance, 0.1)
+	assert.InDelta(t, 30, progress.Duration, 0.1)
+	assert.InDelta(t, 100, progress.RemainingDistance, 0.1)
+	assert.InDelta(t, 20, progress.RemainingDuration, 0.1)
+
+	// positions before the start are projected to the start
+	progress, ok = r.Progress(geo.Point{13.388, 52.516})
+	require.True(t, ok)
+	assert.Equal(t, float64(0), progress.Distance)
+	assert.Equal(t, float64(300), progress.RemainingDistance)
+}
+
+func TestRouteLegGeometry(t *testing.T) {
+	var resp RouteResponse
+	fixturedResponse(t, "route_response_steps", &resp)
+
+	route := resp.Routes[0]
+	leg := route.Legs[0]
+	assert.Equal(t, route.Geometry.PointSet, leg.Geometry().PointSet)
+
+	vertices := leg.Vertices()
+	require.Len(t, vertices, len(route.Geometry.PointSet))
+	assert.InDelta(t, float64(leg.Duration), vertices[len(vertices)-1].Duration, 1e-3)
+
+	p, ok := leg.PositionAt(0)
+	require.True(t, ok)
+	assert.Equal(t, route.Geometry.PointSet[0], p)
+}
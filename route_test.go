@@ -1,9 +1,11 @@
 package osrm
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEmptyRouteRequestOptions(t *testing.T) {
@@ -51,3 +53,70 @@ func TestRouteRequestGeometryOption(t *testing.T) {
 		"annotations=false&continue_straight=true&geometries=polyline6&steps=false",
 		req.request().options.encode())
 }
+
+func TestUnmarshalRouteSteps(t *testing.T) {
+	var resp RouteResponse
+	require.NoError(t, json.Unmarshal(fixturedJSON("route_response_steps"), &resp))
+
+	require.Len(t, resp.Routes, 1)
+	require.Len(t, resp.Routes[0].Legs, 1)
+	steps := resp.Routes[0].Legs[0].Steps
+	require.Len(t, steps, 4)
+
+	depart := steps[0]
+	assert.Equal(t, ManeuverTypeDepart, depart.Maneuver.Type)
+	assert.Empty(t, depart.Maneuver.Modifier)
+	assert.Equal(t, "Friedrichstraße", depart.Name)
+	assert.Equal(t, "B 96", depart.Ref)
+	assert.Equal(t, "ˈfʁiːdʁɪçˌʃtʁaːsə", depart.Pronunciation)
+	assert.Equal(t, TravelModeDriving, depart.Mode)
+	assert.Nil(t, depart.Intersections[0].In)
+
+	ramp := steps[1]
+	assert.Equal(t, ManeuverTypeOnRamp, ramp.Maneuver.Type)
+	assert.Equal(t, ManeuverModifierSlightRight, ramp.Maneuver.Modifier)
+	assert.Equal(t, "A 100", ramp.Ref)
+	assert.Equal(t, "A 100: Hamburg, Dresden", ramp.Destinations)
+	assert.Equal(t, "12", ramp.Exits)
+	require.Len(t, ramp.Intersections, 1)
+	intersection := ramp.Intersections[0]
+	assert.Equal(t, []string{"toll", "motorway"}, intersection.Classes)
+	assert.Equal(t, float32(2.5), intersection.TurnWeight)
+	assert.Equal(t, float32(2), intersection.TurnDuration)
+	assert.Equal(t, []Lane{
+		{Indications: []LaneIndication{LaneIndicationLeft}, Valid: false},
+		{Indications: []LaneIndication{LaneIndicationStraight, LaneIndicationSlightRight}, Valid: true},
+		{Indications: []LaneIndication{LaneIndicationNone}, Valid: true},
+	}, intersection.Lanes)
+
+	rotary := steps[2]
+	assert.Equal(t, ManeuverTypeRotary, rotary.Maneuver.Type)
+	assert.Equal(t, ManeuverModifierRight, rotary.Maneuver.Modifier)
+	require.NotNil(t, rotary.Maneuver.Exit)
+	assert.Equal(t, uint32(2), *rotary.Maneuver.Exit)
+	assert.Equal(t, "Rosenthaler Platz", rotary.RotaryName)
+	assert.Equal(t, "ˈʁoːzn̩ˌtaːlɐ plats", rotary.RotaryPronunciation)
+
+	arrive := steps[3]
+	assert.Equal(t, ManeuverTypeArrive, arrive.Maneuver.Type)
+	assert.Equal(t, TravelModeFerry, arrive.Mode)
+	assert.Nil(t, arrive.Intersections[0].Out)
+}
+
+func TestRouteRequestMultipleAnnotations(t *testing.T) {
+	req := RouteRequest{
+		Annotations: NewAnnotations(AnnotationsDuration, AnnotationsDistance, AnnotationsSpeed, AnnotationsDuration),
+	}
+	assert.Equal(
+		t,
+		"annotations=duration%2Cdistance%2Cspeed&geometries=polyline6",
+		req.request().options.encode())
+}
+
+func TestRouteRequestAlternativesOption(t *testing.T) {
+	req := RouteRequest{Alternatives: AlternativesTrue}
+	assert.Equal(t, "alternatives=true&geometries=polyline6", req.request().options.encode())
+
+	req = RouteRequest{Alternatives: AlternativesNumber(3)}
+	assert.Equal(t, "alternatives=3&geometries=polyline6", req.request().options.encode())
+}
@@ -1,16 +1,112 @@
 package osrm
 
+import (
+	"encoding/json"
+	"math"
+)
+
 // TableRequest represents a request to the table method
 type TableRequest struct {
 	Profile               string
 	Coordinates           Geometry
 	Sources, Destinations []int
+	// Annotations selects the returned tables, AnnotationsDuration is returned by default.
+	// Use NewAnnotations(AnnotationsDuration, AnnotationsDistance) to get both durations and distances.
+	Annotations Annotations
+	Format      Format
 }
 
-// TableResponse resresents a response from the table method
+// TableResponse resresents a response from the table method.
+// OSRM reports unreachable pairs as null values, they are decoded as +Inf and marshaled back to null.
+// Flatbuffers format has no null values, so OSRM reports unreachable pairs as zeros there,
+// use MarkUnreachable to turn them into +Inf.
 type TableResponse struct {
 	ResponseStatus
 	Durations [][]float32 `json:"durations"`
+	Distances [][]float32 `json:"distances"`
+}
+
+// tableJSON is the JSON form of the table response with null values of unreachable pairs
+type tableJSON struct {
+	ResponseStatus
+	Durations [][]*float32 `json:"durations"`
+	Distances [][]*float32 `json:"distances"`
+}
+
+// UnmarshalJSON decodes the response, null values of unreachable pairs are decoded as +Inf
+func (r *TableResponse) UnmarshalJSON(b []byte) error {
+	var t tableJSON
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	r.ResponseStatus = t.ResponseStatus
+	r.Durations = tableMatrixFromJSON(t.Durations)
+	r.Distances = tableMatrixFromJSON(t.Distances)
+	return nil
+}
+
+// MarshalJSON encodes the response, infinite values of unreachable pairs are encoded as null
+func (r TableResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tableJSON{
+		ResponseStatus: r.ResponseStatus,
+		Durations:      tableMatrixToJSON(r.Durations),
+		Distances:      tableMatrixToJSON(r.Distances),
+	})
+}
+
+// MarkUnreachable replaces zeros between different coordinates of the request with +Inf,
+// so flatbuffers responses report unreachable pairs the same way as JSON ones.
+// Different coordinates at the same location can't be told apart and become unreachable too.
+func (r *TableResponse) MarkUnreachable(req TableRequest) {
+	index := func(indices []int, i int) int {
+		if len(indices) == 0 {
+			return i
+		}
+		return indices[i]
+	}
+	for _, m := range [][][]float32{r.Durations, r.Distances} {
+		for i, row := range m {
+			for j, v := range row {
+				if v == 0 && index(req.Sources, i) != index(req.Destinations, j) {
+					row[j] = float32(math.Inf(1))
+				}
+			}
+		}
+	}
+}
+
+func tableMatrixFromJSON(m [][]*float32) [][]float32 {
+	if m == nil {
+		return nil
+	}
+	matrix := make([][]float32, len(m))
+	for i, row := range m {
+		matrix[i] = make([]float32, len(row))
+		for j, v := range row {
+			if v == nil {
+				matrix[i][j] = float32(math.Inf(1))
+			} else {
+				matrix[i][j] = *v
+			}
+		}
+	}
+	return matrix
+}
+
+func tableMatrixToJSON(m [][]float32) [][]*float32 {
+	if m == nil {
+		return nil
+	}
+	matrix := make([][]*float32, len(m))
+	for i, row := range m {
+		matrix[i] = make([]*float32, len(row))
+		for j := range row {
+			if !math.IsInf(float64(row[j]), 1) {
+				matrix[i][j] = &row[j]
+			}
+		}
+	}
+	return matrix
 }
 
 func (r TableRequest) request() *request {
@@ -21,11 +117,15 @@ func (r TableRequest) request() *request {
 	if len(r.Destinations) > 0 {
 		opts.addInt("destinations", r.Destinations...)
 	}
+	if r.Annotations != "" {
+		opts.setStringer("annotations", r.Annotations)
+	}
 
 	return &request{
 		profile: r.Profile,
 		coords:  r.Coordinates,
 		service: "table",
+		format:  r.Format,
 		options: opts,
 	}
 }
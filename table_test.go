@@ -1,9 +1,12 @@
 package osrm
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEmptyTableRequestOptions(t *testing.T) {
@@ -18,3 +21,45 @@ func TestNotEmptyTableRequestOptions(t *testing.T) {
 	}
 	assert.Equal(t, "destinations=1;3&sources=0;1;2", req.request().options.encode())
 }
+
+func TestTableRequestAnnotationsOptions(t *testing.T) {
+	req := TableRequest{
+		Sources:     []int{0},
+		Annotations: NewAnnotations(AnnotationsDuration, AnnotationsDistance),
+	}
+	assert.Equal(t, "annotations=duration%2Cdistance&sources=0", req.request().options.encode())
+}
+
+func TestTableResponseUnreachablePairs(t *testing.T) {
+	body := `{"code":"Ok","message":"","data_version":"","durations":[[0,null],[12.5,0]],"distances":[[0,null],[100,0]]}`
+	var resp TableResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+	inf := float32(math.Inf(1))
+	assert.Equal(t, [][]float32{{0, inf}, {12.5, 0}}, resp.Durations)
+	assert.Equal(t, [][]float32{{0, inf}, {100, 0}}, resp.Distances)
+
+	bytes, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, body, string(bytes))
+
+	var empty TableResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"code":"Ok","durations":[[1]]}`), &empty))
+	assert.Nil(t, empty.Distances)
+}
+
+func TestTableResponseMarkUnreachable(t *testing.T) {
+	inf := float32(math.Inf(1))
+	resp := TableResponse{
+		Durations: [][]float32{{0, 0, 10}, {12.5, 0, 0}},
+		Distances: [][]float32{{0, 0, 100}, {125, 0, 0}},
+	}
+	resp.MarkUnreachable(TableRequest{Sources: []int{0, 2}})
+	assert.Equal(t, [][]float32{{0, inf, 10}, {12.5, inf, 0}}, resp.Durations)
+	assert.Equal(t, [][]float32{{0, inf, 100}, {125, inf, 0}}, resp.Distances)
+
+	resp = TableResponse{Durations: [][]float32{{0, 0}, {5, 0}}}
+	resp.MarkUnreachable(TableRequest{})
+	assert.Equal(t, [][]float32{{0, inf}, {5, 0}}, resp.Durations)
+	assert.Nil(t, resp.Distances)
+}
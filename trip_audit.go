@@ -0,0 +1,184 @@
+package osrm
+
+import (
+	"context"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+const defaultTripAuditFallbackSpeed = 30 / 3.6 // 30 km/h in m/s
+
+// TripSegmentKind represents how distance and duration of a trip segment are reconstructed
+type TripSegmentKind string
+
+// Trip segment kinds
+const (
+	// TripSegmentMatched is a segment of the trace matched to the road network
+	TripSegmentMatched TripSegmentKind = "matched"
+	// TripSegmentRouted is a gap between matchings bridged with the route service
+	TripSegmentRouted TripSegmentKind = "routed"
+	// TripSegmentStraight is a gap between matchings bridged with a straight line
+	TripSegmentStraight TripSegmentKind = "straight"
+)
+
+// TripFlag represents a reason to consider a trip segment suspicious
+type TripFlag string
+
+// Trip flags
+const (
+	// TripFlagLowConfidence is set for matchings with confidence below TripAuditConfig.MinConfidence
+	TripFlagLowConfidence TripFlag = "low confidence"
+	// TripFlagFarSnapping is set for matchings having trace points snapped further than TripAuditConfig.MaxSnapDistance
+	TripFlagFarSnapping TripFlag = "far snapping"
+	// TripFlagStraightLine is set for gaps which couldn't be routed
+	TripFlagStraightLine TripFlag = "straight line"
+)
+
+// TripAuditConfig represents options of a trip reconstruction
+type TripAuditConfig struct {
+	// MinConfidence is the confidence below which a matching is flagged as suspicious.
+	MinConfidence float64
+	// MaxSnapDistance is the distance in meters between a trace point and its matched location
+	// above which a matching is flagged as suspicious. Snapping distance isn't checked if not set.
+	MaxSnapDistance float64
+	// RouteGaps makes gaps between matchings bridged with the route service,
+	// straight lines are used for gaps if not set or if the route service fails.
+	RouteGaps bool
+	// FallbackSpeed in meters per second is used to estimate duration of straight line gaps
+	// if the trace has no timestamps. 30 km/h will be used as default if not set.
+	FallbackSpeed float64
+}
+
+// TripSegment represents a part of a reconstructed trip
+type TripSegment struct {
+	Kind TripSegmentKind
+	// From and To are indexes of the first and the last trace points of the segment
+	From, To int
+	// Distance in meters and Duration in seconds of the segment
+	Distance, Duration float64
+	// Confidence of the matching, it is zero for gaps
+	Confidence float64
+	// FarSnappedPoints are indexes of trace points snapped further than TripAuditConfig.MaxSnapDistance
+	FarSnappedPoints []int
+	Flags            []TripFlag
+}
+
+// Suspicious returns true if the segment has any flags
+func (s TripSegment) Suspicious() bool {
+	return len(s.Flags) > 0
+}
+
+// TripAudit represents a trip reconstructed from a GPS trace, e.g. for fare calculation
+type TripAudit struct {
+	// Distance in meters and Duration in seconds of the whole trip including gaps
+	Distance, Duration float64
+	Segments           []TripSegment
+	// UnmatchedPoints are indexes of trace points which were not matched
+	UnmatchedPoints []int
+}
+
+// Suspicious returns true if any segment of the trip is suspicious
+func (a TripAudit) Suspicious() bool {
+	for _, s := range a.Segments {
+		if s.Suspicious() {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditTrip matches the trace of a completed trip and reconstructs its distance and duration.
+// Gaps between matchings are bridged either with routes or with straight lines,
+// and the segments of doubtful quality are flagged.
+func (o OSRM) AuditTrip(ctx context.Context, r MatchRequest, cfg TripAuditConfig) (*TripAudit, error) {
+	if cfg.FallbackSpeed <= 0 {
+		cfg.FallbackSpeed = defaultTripAuditFallbackSpeed
+	}
+
+	resp, err := o.Match(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &TripAudit{}
+	var matched *TripSegment
+	for i, tp := range resp.Tracepoints {
+		if tp == nil || tp.MatchingIndex >= len(resp.Matchings) {
+			audit.UnmatchedPoints = append(audit.UnmatchedPoints, i)
+			continue
+		}
+		if matched == nil || tp.MatchingIndex != resp.Tracepoints[matched.To].MatchingIndex {
+			if matched != nil {
+				audit.Segments = append(audit.Segments, *matched)
+				audit.Segments = append(audit.Segments, o.bridgeGap(ctx, r, resp.Tracepoints, matched.To, i, cfg))
+			}
+			matched = newMatchedSegment(i, resp.Matchings[tp.MatchingIndex], cfg)
+		}
+		matched.To = i
+		if cfg.MaxSnapDistance > 0 && tp.Distance > cfg.MaxSnapDistance {
+			if len(matched.FarSnappedPoints) == 0 {
+				matched.Flags = append(matched.Flags, TripFlagFarSnapping)
+			}
+			matched.FarSnappedPoints = append(matched.FarSnappedPoints, i)
+		}
+	}
+	if matched != nil {
+		audit.Segments = append(audit.Segments, *matched)
+	}
+
+	for _, s := range audit.Segments {
+		audit.Distance += s.Distance
+		audit.Duration += s.Duration
+	}
+	return audit, nil
+}
+
+func newMatchedSegment(from int, m Matching, cfg TripAuditConfig) *TripSegment {
+	s := &TripSegment{
+		Kind:       TripSegmentMatched,
+		From:       from,
+		Distance:   float64(m.Distance),
+		Duration:   float64(m.Duration),
+		Confidence: m.Confidence,
+	}
+	if m.Confidence < cfg.MinConfidence {
+		s.Flags = append(s.Flags, TripFlagLowConfidence)
+	}
+	return s
+}
+
+// bridgeGap reconstructs a gap between the matched trace points
+func (o OSRM) bridgeGap(ctx context.Context, r MatchRequest, tracepoints []*Tracepoint, from, to int, cfg TripAuditConfig) TripSegment {
+	a, b := tracepoints[from].Location, tracepoints[to].Location
+
+	if cfg.RouteGaps {
+		resp, err := o.Route(ctx, RouteRequest{
+			Profile:     r.Profile,
+			Coordinates: NewGeometryFromPointSet(geo.PointSet{a, b}),
+			Overview:    OverviewFalse,
+		})
+		if err == nil && len(resp.Routes) > 0 {
+			return TripSegment{
+				Kind:     TripSegmentRouted,
+				From:     from,
+				To:       to,
+				Distance: float64(resp.Routes[0].Distance),
+				Duration: float64(resp.Routes[0].Duration),
+			}
+		}
+	}
+
+	s := TripSegment{
+		Kind:     TripSegmentStraight,
+		From:     from,
+		To:       to,
+		Distance: a.GeoDistanceFrom(&b, true),
+		Flags:    []TripFlag{TripFlagStraightLine},
+	}
+	if len(r.Timestamps) > to {
+		s.Duration = float64(r.Timestamps[to] - r.Timestamps[from])
+	} else {
+		s.Duration = s.Distance / cfg.FallbackSpeed
+	}
+	return s
+}
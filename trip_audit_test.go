@@ -0,0 +1,126 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tripAuditTrace = NewGeometryFromPointSet(geo.PointSet{
+	{-73.99, 40.71}, {-73.98, 40.71}, {-73.97, 40.71}, {-73.96, 40.71}, {-73.95, 40.71},
+})
+
+func tripAuditServer(t *testing.T, routeStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/route/") {
+			w.WriteHeader(routeStatus)
+			_, _ = w.Write([]byte(`{"code": "Ok", "routes": [{"distance": 900, "duration": 70}]}`))
+			return
+		}
+
+		resp := MatchResponse{
+			ResponseStatus: ResponseStatus{Code: errorCodeOK},
+			Matchings: []Matching{
+				{Route: Route{Distance: 850, Duration: 60}, Confidence: 0.9},
+				{Route: Route{Distance: 840, Duration: 65}, Confidence: 0.2},
+			},
+			Tracepoints: []*Tracepoint{
+				{Location: geo.Point{-73.99, 40.71}, Distance: 2},
+				{Location: geo.Point{-73.98, 40.71}, Distance: 3},
+				nil,
+				{Location: geo.Point{-73.96, 40.71}, Distance: 40, MatchingIndex: 1},
+				{Location: geo.Point{-73.95, 40.71}, Distance: 1, MatchingIndex: 1},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+}
+
+func TestAuditTripWithRoutedGaps(t *testing.T) {
+	ts := tripAuditServer(t, http.StatusOK)
+	defer ts.Close()
+
+	audit, err := NewFromURL(ts.URL).AuditTrip(context.Background(), MatchRequest{
+		Profile:     "car",
+		Coordinates: tripAuditTrace,
+	}, TripAuditConfig{
+		MinConfidence:   0.5,
+		MaxSnapDistance: 25,
+		RouteGaps:       true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 850.0+900+840, audit.Distance)
+	assert.Equal(t, 60.0+70+65, audit.Duration)
+	assert.Equal(t, []int{2}, audit.UnmatchedPoints)
+	assert.True(t, audit.Suspicious())
+	assert.Equal(t, []TripSegment{
+		{Kind: TripSegmentMatched, From: 0, To: 1, Distance: 850, Duration: 60, Confidence: 0.9},
+		{Kind: TripSegmentRouted, From: 1, To: 3, Distance: 900, Duration: 70},
+		{
+			Kind:             TripSegmentMatched,
+			From:             3,
+			To:               4,
+			Distance:         840,
+			Duration:         65,
+			Confidence:       0.2,
+			FarSnappedPoints: []int{3},
+			Flags:            []TripFlag{TripFlagLowConfidence, TripFlagFarSnapping},
+		},
+	}, audit.Segments)
+}
+
+func TestAuditTripWithStraightGaps(t *testing.T) {
+	ts := tripAuditServer(t, http.StatusInternalServerError)
+	defer ts.Close()
+
+	osrm := NewFromURL(ts.URL)
+	ctx := context.Background()
+
+	t.Run("with timestamps", func(t *testing.T) {
+		audit, err := osrm.AuditTrip(ctx, MatchRequest{
+			Profile:     "car",
+			Coordinates: tripAuditTrace,
+			Timestamps:  []int64{0, 60, 120, 180, 240},
+		}, TripAuditConfig{RouteGaps: true})
+
+		require.NoError(t, err)
+		require.Len(t, audit.Segments, 3)
+		gap := audit.Segments[1]
+		assert.Equal(t, TripSegmentStraight, gap.Kind)
+		assert.Equal(t, []TripFlag{TripFlagStraightLine}, gap.Flags)
+		assert.InDelta(t, 1687.6, gap.Distance, 1)
+		assert.Equal(t, 120.0, gap.Duration)
+		assert.False(t, audit.Segments[0].Suspicious())
+		assert.False(t, audit.Segments[2].Suspicious())
+	})
+
+	t.Run("without timestamps", func(t *testing.T) {
+		audit, err := osrm.AuditTrip(ctx, MatchRequest{
+			Profile:     "car",
+			Coordinates: tripAuditTrace,
+		}, TripAuditConfig{FallbackSpeed: 10})
+
+		require.NoError(t, err)
+		gap := audit.Segments[1]
+		assert.InDelta(t, gap.Distance/10, gap.Duration, 1e-9)
+	})
+}
+
+func TestAuditTripWithMatchError(t *testing.T) {
+	ts := httptest.NewServer(fixturedHTTPHandler("invalid_query_response", func(path, query string) {}))
+	defer ts.Close()
+
+	_, err := NewFromURL(ts.URL).AuditTrip(context.Background(), MatchRequest{
+		Profile:     "car",
+		Coordinates: tripAuditTrace,
+	}, TripAuditConfig{})
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	geo "github.com/paulmach/go.geo"
@@ -18,11 +19,14 @@ const (
 // Geometry represents a points set
 type Geometry struct {
 	geo.Path
+	// Encoding is the format the geometry is marshaled to, polyline6 is used if not set.
+	// Geometries of responses have it set to the requested geometries value, polyline6 is left empty.
+	Encoding Geometries
 }
 
 // NewGeometryFromPath creates a geometry from a path.
 func NewGeometryFromPath(path geo.Path) Geometry {
-	return Geometry{path}
+	return Geometry{Path: path}
 }
 
 // NewGeometryFromPointSet creates a geometry from points set.
@@ -39,7 +43,8 @@ func (g *Geometry) Polyline(factor ...int) string {
 	return g.Encode(factor[0])
 }
 
-// UnmarshalJSON parses a geo path from points set or a polyline
+// UnmarshalJSON parses a geo path from points set or a polyline.
+// Polylines are decoded with polyline6 precision, use DecodeGeometries of the response for other precisions.
 func (g *Geometry) UnmarshalJSON(b []byte) error {
 	if len(b) == 0 {
 		return nil
@@ -59,13 +64,26 @@ func (g *Geometry) UnmarshalJSON(b []byte) error {
 		return fmt.Errorf("unexpected geometry type: %v", geom.Type)
 	}
 	g.Path = *geo.NewPathFromXYSlice(geom.LineString)
+	g.Encoding = GeometriesGeojson
 
 	return nil
 }
 
-// MarshalJSON generates a polyline in Google polyline6 format
+// MarshalJSON generates a polyline in Google polyline6 format,
+// a polyline5 or a GeoJSON line string according to the geometry encoding
 func (g Geometry) MarshalJSON() ([]byte, error) {
-	return json.Marshal(g.Polyline(polyline6Factor))
+	switch g.Encoding {
+	case GeometriesPolyline:
+		return json.Marshal(g.Polyline(polyline5Factor))
+	case GeometriesGeojson:
+		coordinates := make([][]float64, len(g.PointSet))
+		for i, p := range g.PointSet {
+			coordinates[i] = []float64{p.Lng(), p.Lat()}
+		}
+		return json.Marshal(geojson.NewLineStringGeometry(coordinates))
+	default:
+		return json.Marshal(g.Polyline(polyline6Factor))
+	}
 }
 
 // Tidy represents a tidy param for osrm5 match request
@@ -82,7 +100,8 @@ func (t Tidy) String() string {
 	return string(t)
 }
 
-// Annotations represents a annotations param for osrm5 request
+// Annotations represents a annotations param for osrm5 request.
+// Use NewAnnotations to request several kinds of annotations at once.
 type Annotations string
 
 // Supported annotations param values
@@ -102,6 +121,43 @@ func (a Annotations) String() string {
 	return string(a)
 }
 
+// NewAnnotations combines annotation kinds into a single annotations param value,
+// e.g. NewAnnotations(AnnotationsDuration, AnnotationsDistance, AnnotationsSpeed).
+// Duplicated and empty kinds are omitted. AnnotationsTrue requests all kinds, thus it absorbs the others,
+// while AnnotationsFalse is dropped if combined with specific kinds.
+func NewAnnotations(kinds ...Annotations) Annotations {
+	var s []string
+	seen := make(map[Annotations]bool, len(kinds))
+	for _, k := range kinds {
+		if k == AnnotationsTrue {
+			return AnnotationsTrue
+		}
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		if k != AnnotationsFalse {
+			s = append(s, k.String())
+		}
+	}
+	if len(s) == 0 && seen[AnnotationsFalse] {
+		return AnnotationsFalse
+	}
+	return Annotations(strings.Join(s, ","))
+}
+
+// Kinds returns annotation kinds combined in the annotations param value
+func (a Annotations) Kinds() []Annotations {
+	if a == "" {
+		return nil
+	}
+	var kinds []Annotations
+	for _, k := range strings.Split(a.String(), ",") {
+		kinds = append(kinds, Annotations(k))
+	}
+	return kinds
+}
+
 // Steps represents a steps param for osrm5 request
 type Steps string
 
@@ -135,6 +191,7 @@ type Geometries string
 
 // Supported geometries param values
 const (
+	GeometriesPolyline  Geometries = "polyline"
 	GeometriesPolyline6 Geometries = "polyline6"
 	GeometriesGeojson   Geometries = "geojson"
 )
@@ -174,12 +231,47 @@ func (c ContinueStraight) String() string {
 	return string(c)
 }
 
+// Format represents a response format of OSRM API
+type Format string
+
+// Supported response formats
+const (
+	FormatJSON        Format = "json"
+	FormatFlatbuffers Format = "flatbuffers"
+)
+
+// String returns Format as a string
+func (f Format) String() string {
+	return string(f)
+}
+
+// Alternatives represents alternatives param for osrm5 route request
+type Alternatives string
+
+// Supported alternatives param values, use AlternativesNumber to request a specific number of alternatives
+const (
+	AlternativesTrue  Alternatives = "true"
+	AlternativesFalse Alternatives = "false"
+)
+
+// AlternativesNumber requests up to n alternative routes
+func AlternativesNumber(n int) Alternatives {
+	return Alternatives(strconv.Itoa(n))
+}
+
+// String returns Alternatives as a string
+func (a Alternatives) String() string {
+	return string(a)
+}
+
 // request contains parameters for OSRM query
 type request struct {
-	profile string
-	coords  Geometry
-	service string
-	options options
+	profile    string
+	coords     Geometry
+	service    string
+	format     Format
+	geometries Geometries
+	options    options
 }
 
 // URL generates a url for OSRM request
@@ -201,6 +293,9 @@ func (r *request) URL(serverURL string) (string, error) {
 		r.profile, // profile
 		"polyline(" + url.PathEscape(r.coords.Polyline(polyline5Factor)) + ")", // coordinates
 	}, "/")
+	if r.format != "" {
+		url += "." + r.format.String() // format
+	}
 	if len(r.options) > 0 {
 		url += "?" + r.options.encode() // options
 	}
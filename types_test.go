@@ -121,3 +121,64 @@ func TestRequestURLWithoutCoords(t *testing.T) {
 	assert.Equal(t, ErrNoCoordinates, err)
 	assert.Empty(t, url)
 }
+
+func TestMarshalStepManeuver(t *testing.T) {
+	exit := uint32(3)
+	bytes, err := json.Marshal(StepManeuver{
+		Type:     ManeuverTypeRoundabout,
+		Modifier: ManeuverModifierSlightLeft,
+		Exit:     &exit,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"location": [0, 0],
+		"bearing_before": 0,
+		"bearing_after": 0,
+		"type": "roundabout",
+		"modifier": "slight left",
+		"exit": 3
+	}`, string(bytes))
+
+	var m StepManeuver
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "some future type", "modifier": "uturn"}`), &m))
+	assert.Equal(t, ManeuverType("some future type"), m.Type)
+	assert.Equal(t, ManeuverModifierUTurn, m.Modifier)
+}
+
+func TestAnnotationsKinds(t *testing.T) {
+	a := NewAnnotations(AnnotationsSpeed, "", AnnotationsWeight, AnnotationsSpeed)
+	assert.Equal(t, Annotations("speed,weight"), a)
+	assert.Equal(t, []Annotations{AnnotationsSpeed, AnnotationsWeight}, a.Kinds())
+	assert.Equal(t, []Annotations{AnnotationsTrue}, AnnotationsTrue.Kinds())
+	assert.Empty(t, NewAnnotations())
+
+	assert.Equal(t, AnnotationsTrue, NewAnnotations(AnnotationsSpeed, AnnotationsTrue))
+	assert.Equal(t, AnnotationsSpeed, NewAnnotations(AnnotationsFalse, AnnotationsSpeed))
+	assert.Equal(t, AnnotationsFalse, NewAnnotations(AnnotationsFalse, AnnotationsFalse))
+	assert.Nil(t, Annotations("").Kinds())
+}
+
+func TestMarshalGeometryEncodings(t *testing.T) {
+	g := NewGeometryFromPointSet(geo.PointSet{
+		{-73.990195, 40.714703},
+		{-73.989754, 40.715553},
+	})
+
+	cases := []struct {
+		encoding Geometries
+		expected string
+	}{
+		{"", `"}{_tlAdb_clCct@qZ"`},
+		{GeometriesPolyline6, `"` + g.Polyline(polyline6Factor) + `"`},
+		{GeometriesPolyline, `"` + g.Polyline(polyline5Factor) + `"`},
+		{GeometriesGeojson, `{"type":"LineString","coordinates":[[-73.990195,40.714703],[-73.989754,40.715553]]}`},
+	}
+	for _, c := range cases {
+		t.Run(string(c.encoding), func(t *testing.T) {
+			g.Encoding = c.encoding
+			bytes, err := json.Marshal(g)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, string(bytes))
+		})
+	}
+}
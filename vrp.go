@@ -0,0 +1,464 @@
+package osrm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+const (
+	defaultVRPMaxIterations = 1000
+	vrpOrOptMaxSegment      = 3
+	vrpEpsilon              = 1e-6
+)
+
+// VRPVehicle represents a vehicle serving stops of the vehicle routing problem
+type VRPVehicle struct {
+	// Start and End are matrix indices of the locations the vehicle departs from and returns to
+	Start, End int
+	// Open vehicles finish at their last stop, End is ignored
+	Open bool
+	// Capacity is the maximal load of the vehicle, there is no limit if not set
+	Capacity int
+}
+
+// VRPStop represents a location to be visited by a vehicle
+type VRPStop struct {
+	// Location is the matrix index of the stop
+	Location int
+	// Demand changes the load of the vehicle, it's positive for pickups and negative for dropoffs.
+	// Dropoffs without a pickup pair are deliveries loaded at the start of the route.
+	Demand int
+	// ServiceTime is spent at the stop
+	ServiceTime time.Duration
+	// Earliest and Latest bound the start of the service since the departure of vehicles.
+	// Vehicles arriving earlier wait, there is no latest time if not set.
+	Earliest, Latest time.Duration
+}
+
+// VRPPair represents stops served by the same vehicle with the pickup preceding the dropoff
+type VRPPair struct {
+	// Pickup and Dropoff are indices of the stops
+	Pickup, Dropoff int
+}
+
+// VRPProblem represents a vehicle routing problem over a duration matrix
+type VRPProblem struct {
+	// Durations in seconds between matrix locations, infinite durations are never traveled
+	Durations [][]float64
+	Vehicles  []VRPVehicle
+	Stops     []VRPStop
+	Pairs     []VRPPair
+}
+
+// NewVRPProblem creates a problem with durations of the table, vehicles and stops are to be added.
+// Unreachable pairs have infinite durations, so stops which can't be reached are left unassigned.
+// Flatbuffers tables report them as zeros, call TableResponse.MarkUnreachable first.
+func NewVRPProblem(table TableResponse) VRPProblem {
+	p := VRPProblem{Durations: make([][]float64, len(table.Durations))}
+	for i, row := range table.Durations {
+		p.Durations[i] = make([]float64, len(row))
+		for j, d := range row {
+			p.Durations[i][j] = float64(d)
+		}
+	}
+	return p
+}
+
+// VRPOptions represents options of the vehicle routing solver
+type VRPOptions struct {
+	// MaxIterations is the maximal number of local search moves, 1000 will be used as default if not set.
+	MaxIterations int
+}
+
+// VRPRoute represents stops visited by a vehicle
+type VRPRoute struct {
+	// Vehicle is the index of the vehicle
+	Vehicle int
+	// Stops are indices of the stops in the order of visiting
+	Stops []int
+	// Arrivals are times in seconds since the departure when the service at the stops starts
+	Arrivals []float64
+	// Duration in seconds since the departure till the arrival to the end of the route
+	Duration float64
+}
+
+// VRPSolution represents routes of vehicles solving the problem
+type VRPSolution struct {
+	// Routes of the vehicles having stops
+	Routes []VRPRoute
+	// Unassigned are indices of the stops which can't be served
+	Unassigned []int
+	// Duration in seconds is the sum of route durations
+	Duration float64
+}
+
+// SolveVRP solves the vehicle routing problem minimizing the total duration of routes.
+// Stops are inserted into routes at the cheapest feasible positions, pairs are inserted together,
+// then routes are improved with relocate, exchange, or-opt and 2-opt moves until no move improves them.
+// The solver is heuristic and intended for tens of stops.
+func SolveVRP(p VRPProblem, opts VRPOptions) (*VRPSolution, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = defaultVRPMaxIterations
+	}
+
+	s := newVRPSolver(p)
+	s.construct()
+	for i := 0; i < opts.MaxIterations; i++ {
+		if !s.relocate() && !s.exchange() && !s.orOpt() && !s.twoOpt() {
+			break
+		}
+	}
+	// moves may free room for the stops left unassigned by the construction
+	s.construct()
+	return s.solution(), nil
+}
+
+func (p VRPProblem) validate() error {
+	if len(p.Vehicles) == 0 {
+		return ErrVRPNoVehicles
+	}
+	location := func(i int) bool { return i >= 0 && i < len(p.Durations) && len(p.Durations[i]) == len(p.Durations) }
+	for i, v := range p.Vehicles {
+		if !location(v.Start) || (!v.Open && !location(v.End)) {
+			return fmt.Errorf("%w: vehicle %d", ErrVRPInvalidLocation, i)
+		}
+	}
+	for i, s := range p.Stops {
+		if !location(s.Location) {
+			return fmt.Errorf("%w: stop %d", ErrVRPInvalidLocation, i)
+		}
+	}
+	paired := make(map[int]bool, 2*len(p.Pairs))
+	for i, pair := range p.Pairs {
+		for _, s := range []int{pair.Pickup, pair.Dropoff} {
+			if s < 0 || s >= len(p.Stops) || paired[s] {
+				return fmt.Errorf("%w: pair %d", ErrVRPInvalidPair, i)
+			}
+			paired[s] = true
+		}
+	}
+	return nil
+}
+
+// vrpSolver keeps a route per vehicle, units are single stops or pairs moved together
+type vrpSolver struct {
+	p        VRPProblem
+	partner  []int // the other stop of the pair or -1
+	pickup   []bool
+	units    [][]int
+	routes   [][]int
+	costs    []float64
+	assigned []bool // by unit
+}
+
+func newVRPSolver(p VRPProblem) *vrpSolver {
+	s := &vrpSolver{
+		p:       p,
+		partner: make([]int, len(p.Stops)),
+		pickup:  make([]bool, len(p.Stops)),
+		routes:  make([][]int, len(p.Vehicles)),
+		costs:   make([]float64, len(p.Vehicles)),
+	}
+	for i := range s.partner {
+		s.partner[i] = -1
+	}
+	for _, pair := range p.Pairs {
+		s.partner[pair.Pickup], s.partner[pair.Dropoff] = pair.Dropoff, pair.Pickup
+		s.pickup[pair.Pickup] = true
+		s.units = append(s.units, []int{pair.Pickup, pair.Dropoff})
+	}
+	for i := range p.Stops {
+		if s.partner[i] < 0 {
+			s.units = append(s.units, []int{i})
+		}
+	}
+	s.assigned = make([]bool, len(s.units))
+	return s
+}
+
+// schedule returns service start times at the stops and the route duration, ok is false for infeasible routes
+func (s *vrpSolver) schedule(vehicle int, route []int) (arrivals []float64, duration float64, ok bool) {
+	if len(route) == 0 {
+		return nil, 0, true
+	}
+	v := s.p.Vehicles[vehicle]
+
+	// deliveries are on board from the start
+	load := 0
+	for _, stop := range route {
+		if d := s.p.Stops[stop].Demand; d < 0 && s.partner[stop] < 0 {
+			load -= d
+		}
+	}
+	if v.Capacity > 0 && load > v.Capacity {
+		return nil, 0, false
+	}
+
+	visited := make(map[int]bool, len(route))
+	location := v.Start
+	arrivals = make([]float64, len(route))
+	for i, stop := range route {
+		if p := s.partner[stop]; p >= 0 && visited[p] == s.pickup[stop] {
+			return nil, 0, false
+		}
+		visited[stop] = true
+
+		st := s.p.Stops[stop]
+		duration += s.p.Durations[location][st.Location]
+		duration = math.Max(duration, st.Earliest.Seconds())
+		if math.IsInf(duration, 1) || (st.Latest > 0 && duration > st.Latest.Seconds()) {
+			return nil, 0, false
+		}
+		arrivals[i] = duration
+		duration += st.ServiceTime.Seconds()
+
+		load += st.Demand
+		if load < 0 || (v.Capacity > 0 && load > v.Capacity) {
+			return nil, 0, false
+		}
+		location = st.Location
+	}
+	if !v.Open {
+		duration += s.p.Durations[location][v.End]
+	}
+	if math.IsInf(duration, 1) {
+		return nil, 0, false
+	}
+	return arrivals, duration, true
+}
+
+func (s *vrpSolver) cost(vehicle int, route []int) (float64, bool) {
+	_, duration, ok := s.schedule(vehicle, route)
+	return duration, ok
+}
+
+// bestInsertion returns the cheapest feasible route with the unit inserted
+func (s *vrpSolver) bestInsertion(unit []int) (vehicle int, route []int, delta float64, ok bool) {
+	delta = math.Inf(1)
+	for v, r := range s.routes {
+		for i := 0; i <= len(r); i++ {
+			if len(unit) == 1 {
+				candidate := insertStops(r, i, unit[0])
+				if c, feasible := s.cost(v, candidate); feasible && c-s.costs[v] < delta {
+					vehicle, route, delta, ok = v, candidate, c-s.costs[v], true
+				}
+				continue
+			}
+			for j := i; j <= len(r); j++ {
+				candidate := insertStops(insertStops(r, j, unit[1]), i, unit[0])
+				if c, feasible := s.cost(v, candidate); feasible && c-s.costs[v] < delta {
+					vehicle, route, delta, ok = v, candidate, c-s.costs[v], true
+				}
+			}
+		}
+	}
+	return vehicle, route, delta, ok
+}
+
+// construct inserts unassigned units one by one choosing the cheapest insertion among all of them
+func (s *vrpSolver) construct() {
+	for {
+		best, bestVehicle, bestDelta := -1, 0, math.Inf(1)
+		var bestRoute []int
+		for u, unit := range s.units {
+			if s.assigned[u] {
+				continue
+			}
+			if v, r, d, ok := s.bestInsertion(unit); ok && d < bestDelta {
+				best, bestVehicle, bestRoute, bestDelta = u, v, r, d
+			}
+		}
+		if best < 0 {
+			return
+		}
+		s.assigned[best] = true
+		s.setRoute(bestVehicle, bestRoute)
+	}
+}
+
+func (s *vrpSolver) setRoute(vehicle int, route []int) {
+	s.routes[vehicle] = route
+	s.costs[vehicle], _ = s.cost(vehicle, route)
+}
+
+// relocate moves a unit to the cheapest position in any route
+func (s *vrpSolver) relocate() bool {
+	for u, unit := range s.units {
+		if !s.assigned[u] {
+			continue
+		}
+		from := s.routeOf(unit[0])
+		removed := removeStops(s.routes[from], unit)
+		removedCost, feasible := s.cost(from, removed)
+		if !feasible {
+			// tables breaking the triangle inequality may make the route longer without the unit and miss a latest time
+			continue
+		}
+
+		original, originalCost := s.routes[from], s.costs[from]
+		s.routes[from], s.costs[from] = removed, removedCost
+		v, r, delta, ok := s.bestInsertion(unit)
+		if ok && removedCost+delta < originalCost-vrpEpsilon {
+			s.setRoute(v, r)
+			return true
+		}
+		s.routes[from], s.costs[from] = original, originalCost
+	}
+	return false
+}
+
+// exchange swaps stops of different routes, pairs aren't swapped
+func (s *vrpSolver) exchange() bool {
+	for va := range s.routes {
+		for vb := va + 1; vb < len(s.routes); vb++ {
+			for i, a := range s.routes[va] {
+				for j, b := range s.routes[vb] {
+					if s.partner[a] >= 0 || s.partner[b] >= 0 {
+						continue
+					}
+					ra := append([]int{}, s.routes[va]...)
+					rb := append([]int{}, s.routes[vb]...)
+					ra[i], rb[j] = b, a
+					ca, okA := s.cost(va, ra)
+					cb, okB := s.cost(vb, rb)
+					if okA && okB && ca+cb < s.costs[va]+s.costs[vb]-vrpEpsilon {
+						s.setRoute(va, ra)
+						s.setRoute(vb, rb)
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// orOpt moves a segment of consecutive stops to another position in the same route
+func (s *vrpSolver) orOpt() bool {
+	for v, r := range s.routes {
+		for length := 1; length <= vrpOrOptMaxSegment; length++ {
+			for i := 0; i+length <= len(r); i++ {
+				segment := r[i : i+length]
+				rest := append(append([]int{}, r[:i]...), r[i+length:]...)
+				for k := 0; k <= len(rest); k++ {
+					if k == i {
+						continue
+					}
+					candidate := append(append(append([]int{}, rest[:k]...), segment...), rest[k:]...)
+					if c, ok := s.cost(v, candidate); ok && c < s.costs[v]-vrpEpsilon {
+						s.setRoute(v, candidate)
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// twoOpt reverses a part of a route
+func (s *vrpSolver) twoOpt() bool {
+	for v, r := range s.routes {
+		for i := 0; i < len(r); i++ {
+			for j := i + 1; j < len(r); j++ {
+				candidate := append([]int{}, r...)
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					candidate[a], candidate[b] = candidate[b], candidate[a]
+				}
+				if c, ok := s.cost(v, candidate); ok && c < s.costs[v]-vrpEpsilon {
+					s.setRoute(v, candidate)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (s *vrpSolver) routeOf(stop int) int {
+	for v, r := range s.routes {
+		for _, x := range r {
+			if x == stop {
+				return v
+			}
+		}
+	}
+	return -1
+}
+
+func (s *vrpSolver) solution() *VRPSolution {
+	sol := &VRPSolution{}
+	for v, r := range s.routes {
+		if len(r) == 0 {
+			continue
+		}
+		arrivals, duration, _ := s.schedule(v, r)
+		sol.Routes = append(sol.Routes, VRPRoute{Vehicle: v, Stops: r, Arrivals: arrivals, Duration: duration})
+		sol.Duration += duration
+	}
+	for u, unit := range s.units {
+		if !s.assigned[u] {
+			sol.Unassigned = append(sol.Unassigned, unit...)
+		}
+	}
+	return sol
+}
+
+func insertStops(route []int, i int, stops ...int) []int {
+	r := make([]int, 0, len(route)+len(stops))
+	r = append(r, route[:i]...)
+	r = append(r, stops...)
+	return append(r, route[i:]...)
+}
+
+func removeStops(route []int, stops []int) []int {
+	r := make([]int, 0, len(route))
+	for _, x := range route {
+		keep := true
+		for _, s := range stops {
+			keep = keep && x != s
+		}
+		if keep {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+// VRPRoutes requests full geometries of the solution routes, locations are coordinates of the matrix.
+// Routes go from the start of the vehicle through its stops to the end unless the vehicle is open,
+// they are returned in the order of the solution routes.
+func (o OSRM) VRPRoutes(ctx context.Context, profile string, locations geo.PointSet, p VRPProblem, s *VRPSolution) ([]Route, error) {
+	routes := make([]Route, len(s.Routes))
+	for i, r := range s.Routes {
+		v := p.Vehicles[r.Vehicle]
+		ps := geo.PointSet{locations[v.Start]}
+		for _, stop := range r.Stops {
+			ps = append(ps, locations[p.Stops[stop].Location])
+		}
+		if !v.Open {
+			ps = append(ps, locations[v.End])
+		}
+
+		resp, err := o.Route(ctx, RouteRequest{
+			Profile:     profile,
+			Coordinates: NewGeometryFromPointSet(ps),
+			Overview:    OverviewFull,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Routes) == 0 {
+			return nil, fmt.Errorf("osrm5: no route for vehicle %d", r.Vehicle)
+		}
+		routes[i] = resp.Routes[0]
+	}
+	return routes, nil
+}
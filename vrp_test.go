@@ -0,0 +1,205 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lineVRPProblem places locations on a line with a minute between neighbors, the first location is the depot
+func lineVRPProblem(positions ...float64) VRPProblem {
+	p := VRPProblem{Durations: make([][]float64, len(positions))}
+	for i, a := range positions {
+		p.Durations[i] = make([]float64, len(positions))
+		for j, b := range positions {
+			p.Durations[i][j] = math.Abs(a-b) * 60
+		}
+	}
+	for i := 1; i < len(positions); i++ {
+		p.Stops = append(p.Stops, VRPStop{Location: i, Demand: 1})
+	}
+	return p
+}
+
+func TestNewVRPProblem(t *testing.T) {
+	p := NewVRPProblem(TableResponse{Durations: [][]float32{{0, 1.5}, {2, 0}}})
+	assert.Equal(t, [][]float64{{0, 1.5}, {2, 0}}, p.Durations)
+
+	// the stop is unreachable in the table
+	var table TableResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"code":"Ok","durations":[[0,60,null],[60,0,null],[null,null,0]]}`), &table))
+	p = NewVRPProblem(table)
+	p.Vehicles = []VRPVehicle{{Start: 0, End: 0}}
+	p.Stops = []VRPStop{{Location: 1}, {Location: 2}}
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, s.Unassigned)
+	assert.Equal(t, 120.0, s.Duration)
+}
+
+func TestSolveVRPSingleVehicle(t *testing.T) {
+	p := lineVRPProblem(0, 5, 1, 3, 2, 4)
+	p.Vehicles = []VRPVehicle{{Start: 0, End: 0}}
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	require.Len(t, s.Routes, 1)
+	assert.Empty(t, s.Unassigned)
+	assert.Equal(t, 600.0, s.Duration)
+
+	// both directions are optimal
+	stops := s.Routes[0].Stops
+	if stops[0] == 0 {
+		stops = reverseInts(stops)
+	}
+	assert.Equal(t, []int{1, 3, 2, 4, 0}, stops)
+}
+
+func reverseInts(v []int) []int {
+	r := make([]int, len(v))
+	for i, x := range v {
+		r[len(v)-1-i] = x
+	}
+	return r
+}
+
+func TestSolveVRPCapacity(t *testing.T) {
+	p := lineVRPProblem(0, -3, 1, -1, 3, -2, 2)
+	p.Vehicles = []VRPVehicle{{Capacity: 3}, {Capacity: 3}}
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	require.Len(t, s.Routes, 2)
+	assert.Empty(t, s.Unassigned)
+	assert.Equal(t, 720.0, s.Duration)
+	for _, r := range s.Routes {
+		assert.Len(t, r.Stops, 3)
+		assert.Equal(t, 360.0, r.Duration)
+	}
+}
+
+func TestSolveVRPDeliveries(t *testing.T) {
+	p := lineVRPProblem(0, -2, -1, 1, 2, 3)
+	for i := range p.Stops {
+		p.Stops[i].Demand = -1
+	}
+	p.Vehicles = []VRPVehicle{{Capacity: 2}, {Capacity: 2}}
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	// the vehicles are loaded at the depot, so only four deliveries fit
+	assert.Len(t, s.Unassigned, 1)
+	require.Len(t, s.Routes, 2)
+	for _, r := range s.Routes {
+		assert.Len(t, r.Stops, 2)
+	}
+}
+
+func TestSolveVRPTimeWindows(t *testing.T) {
+	p := lineVRPProblem(0, 1, 2, 5)
+	p.Vehicles = []VRPVehicle{{Open: true}}
+	p.Stops[0].Earliest = 5 * time.Minute
+	p.Stops[1].ServiceTime = time.Minute
+	p.Stops[2].Latest = 2 * time.Minute
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, s.Unassigned)
+	require.Len(t, s.Routes, 1)
+
+	// the farther stop is served first while the closer one waits for its window
+	r := s.Routes[0]
+	require.Equal(t, []int{1, 0}, r.Stops)
+	assert.Equal(t, []float64{120, 300}, r.Arrivals)
+	assert.Equal(t, 300.0, r.Duration)
+}
+
+func TestSolveVRPTriangleInequality(t *testing.T) {
+	// the second stop is reachable in time only through the first one
+	p := VRPProblem{
+		Durations: [][]float64{{0, 60, 600}, {60, 0, 60}, {60, 60, 0}},
+		Stops:     []VRPStop{{Location: 1}, {Location: 2, Latest: 5 * time.Minute}},
+		Vehicles:  []VRPVehicle{{}, {}},
+	}
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, s.Unassigned)
+	require.Len(t, s.Routes, 1)
+	assert.Equal(t, []int{0, 1}, s.Routes[0].Stops)
+	assert.Equal(t, 180.0, s.Routes[0].Duration)
+}
+
+func TestSolveVRPPairs(t *testing.T) {
+	p := lineVRPProblem(0, 1, 4, 2, 3)
+	p.Stops[1].Demand, p.Stops[0].Demand = 1, -1
+	p.Stops[2].Demand, p.Stops[3].Demand = 1, -1
+	// rides from 4 to 1 and from 2 to 3
+	p.Pairs = []VRPPair{{Pickup: 1, Dropoff: 0}, {Pickup: 2, Dropoff: 3}}
+	p.Vehicles = []VRPVehicle{{Open: true, Capacity: 1}}
+
+	s, err := SolveVRP(p, VRPOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, s.Unassigned)
+	require.Len(t, s.Routes, 1)
+	assert.Equal(t, []int{2, 3, 1, 0}, s.Routes[0].Stops)
+	assert.Equal(t, 420.0, s.Routes[0].Duration)
+}
+
+func TestSolveVRPInvalidProblem(t *testing.T) {
+	p := lineVRPProblem(0, 1)
+	_, err := SolveVRP(p, VRPOptions{})
+	assert.Equal(t, ErrVRPNoVehicles, err)
+
+	p.Vehicles = []VRPVehicle{{Start: 0, End: 2}}
+	_, err = SolveVRP(p, VRPOptions{})
+	assert.True(t, errors.Is(err, ErrVRPInvalidLocation))
+	assert.EqualError(t, err, "osrm5: location is out of the duration matrix: vehicle 0")
+
+	p.Vehicles[0].Open = true
+	p.Pairs = []VRPPair{{Pickup: 0, Dropoff: 0}}
+	_, err = SolveVRP(p, VRPOptions{})
+	assert.True(t, errors.Is(err, ErrVRPInvalidPair))
+}
+
+func TestVRPRoutes(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "full", r.URL.Query().Get("overview"))
+		_, _ = w.Write([]byte(`{"code":"Ok","routes":[{"distance":10,"duration":2}]}`))
+	}))
+	defer ts.Close()
+
+	locations := dispatchLocations(0, 1, 2)
+	p := lineVRPProblem(0, 1, 2)
+	p.Vehicles = []VRPVehicle{{Start: 0, End: 0}, {Start: 0, Open: true}}
+	s := &VRPSolution{Routes: []VRPRoute{
+		{Vehicle: 0, Stops: []int{1}},
+		{Vehicle: 1, Stops: []int{0}},
+	}}
+
+	routes, err := NewFromURL(ts.URL).VRPRoutes(context.Background(), "car", locations, p, s)
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	assert.Equal(t, float32(10), routes[0].Distance)
+
+	require.Len(t, paths, 2)
+	pointsOf := func(path string) geo.PointSet {
+		encoded := strings.TrimSuffix(strings.TrimPrefix(path, "/route/v1/car/polyline("), ")")
+		return geo.NewPathFromEncoding(encoded, polyline5Factor).PointSet
+	}
+	assert.Len(t, pointsOf(paths[0]), 3)
+	assert.Len(t, pointsOf(paths[1]), 2)
+	assert.InDelta(t, locations[2].Lat(), pointsOf(paths[0])[1].Lat(), 1e-5)
+}